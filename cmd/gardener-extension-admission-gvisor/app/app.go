@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	controllercmd "github.com/gardener/gardener/extensions/pkg/controller/cmd"
+	webhookcmd "github.com/gardener/gardener/extensions/pkg/webhook/cmd"
+	gardencoreinstall "github.com/gardener/gardener/pkg/apis/core/install"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	gardenerhealthz "github.com/gardener/gardener/pkg/healthz"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/version/verflag"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	admissioncmd "github.com/gardener/gardener-extension-runtime-gvisor/pkg/admission/cmd"
+	gvisorinstall "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/install"
+)
+
+// Name is the name of the admission component.
+const Name = "admission-gvisor"
+
+var log = logf.Log.WithName("gardener-extension-admission-gvisor")
+
+// NewAdmissionCommand creates a new command that is used to start the gVisor admission webhook.
+func NewAdmissionCommand(ctx context.Context) *cobra.Command {
+	var (
+		restOpts = &controllercmd.RESTOptions{}
+		mgrOpts  = &controllercmd.ManagerOptions{
+			LeaderElection:          true,
+			LeaderElectionID:        controllercmd.LeaderElectionNameID(Name),
+			LeaderElectionNamespace: os.Getenv("LEADER_ELECTION_NAMESPACE"),
+			WebhookServerPort:       443,
+			WebhookCertDir:          "/tmp/admission-gvisor-cert",
+		}
+
+		// options for the webhook server
+		webhookServerOptions = &webhookcmd.ServerOptions{
+			Namespace: os.Getenv("WEBHOOK_CONFIG_NAMESPACE"),
+		}
+		webhookSwitches = admissioncmd.GardenWebhookSwitchOptions()
+		webhookOptions  = webhookcmd.NewAddToManagerOptions(
+			Name,
+			"",
+			nil,
+			nil,
+			webhookServerOptions,
+			webhookSwitches,
+		)
+
+		aggOption = controllercmd.NewOptionAggregator(
+			restOpts,
+			mgrOpts,
+			webhookOptions,
+		)
+	)
+
+	cmd := &cobra.Command{
+		Use: Name,
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+			// Act on version flag, if one was specified
+			verflag.PrintAndExitIfRequested()
+
+			// The webhook validates Shoots, hence it is registered in and talks to the garden cluster. The kubeconfig
+			// for it is either injected by gardener-operator or mounted by the chart.
+			if gardenKubeconfig := os.Getenv(v1beta1constants.EnvGenericGardenKubeconfig); gardenKubeconfig != "" {
+				log.Info("Getting rest config for garden from environment", "env", v1beta1constants.EnvGenericGardenKubeconfig, "path", gardenKubeconfig)
+				restOpts.Kubeconfig = gardenKubeconfig
+			}
+
+			if err := aggOption.Complete(); err != nil {
+				return fmt.Errorf("error completing options: %w", err)
+			}
+
+			// Leader election and the certificates of the webhook server are kept in the runtime cluster in which
+			// the admission component is running.
+			inClusterConfig, err := rest.InClusterConfig()
+			if err != nil {
+				return fmt.Errorf("could not get in-cluster config: %w", err)
+			}
+
+			managerOptions := mgrOpts.Completed().Options()
+			managerOptions.LeaderElectionConfig = inClusterConfig
+
+			mgr, err := manager.New(restOpts.Completed().Config, managerOptions)
+			if err != nil {
+				return fmt.Errorf("could not instantiate manager: %w", err)
+			}
+
+			gardencoreinstall.Install(mgr.GetScheme())
+			gvisorinstall.Install(mgr.GetScheme())
+
+			if err := mgr.AddReadyzCheck("informer-sync", gardenerhealthz.NewCacheSyncHealthz(mgr.GetCache())); err != nil {
+				return fmt.Errorf("could not add ready check for informers: %w", err)
+			}
+
+			if err := mgr.AddReadyzCheck("webhook-server", mgr.GetWebhookServer().StartedChecker()); err != nil {
+				return fmt.Errorf("could not add ready check for webhook server: %w", err)
+			}
+
+			sourceCluster, err := cluster.New(inClusterConfig, func(opts *cluster.Options) {
+				opts.Logger = log
+				opts.Cache.DefaultNamespaces = map[string]cache.Config{webhookServerOptions.Namespace: {}}
+			})
+			if err != nil {
+				return fmt.Errorf("could not instantiate runtime cluster: %w", err)
+			}
+
+			if err := mgr.AddReadyzCheck("source-informer-sync", gardenerhealthz.NewCacheSyncHealthz(sourceCluster.GetCache())); err != nil {
+				return fmt.Errorf("could not add ready check for runtime cluster informers: %w", err)
+			}
+
+			if err := mgr.Add(sourceCluster); err != nil {
+				return fmt.Errorf("could not add runtime cluster to manager: %w", err)
+			}
+
+			if _, err := webhookOptions.Completed().AddToManager(ctx, mgr, sourceCluster); err != nil {
+				return fmt.Errorf("could not add webhooks to manager: %w", err)
+			}
+
+			if err := mgr.Start(ctx); err != nil {
+				return fmt.Errorf("error running manager: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	verflag.AddFlags(cmd.Flags())
+	aggOption.AddFlags(cmd.Flags())
+
+	return cmd
+}
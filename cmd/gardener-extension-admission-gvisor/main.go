@@ -0,0 +1,25 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+
+	"github.com/gardener/gardener/pkg/logger"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/cmd/gardener-extension-admission-gvisor/app"
+)
+
+func main() {
+	runtimelog.SetLogger(logger.MustNewZapLogger(logger.InfoLevel, logger.FormatJSON))
+	cmd := app.NewAdmissionCommand(signals.SetupSignalHandler())
+
+	if err := cmd.Execute(); err != nil {
+		runtimelog.Log.Error(err, "Error executing the main admission command")
+		os.Exit(1)
+	}
+}
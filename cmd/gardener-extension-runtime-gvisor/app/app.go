@@ -13,6 +13,7 @@ import (
 	controllercmd "github.com/gardener/gardener/extensions/pkg/controller/cmd"
 	"github.com/gardener/gardener/extensions/pkg/controller/heartbeat"
 	heartbeatcmd "github.com/gardener/gardener/extensions/pkg/controller/heartbeat/cmd"
+	webhookcmd "github.com/gardener/gardener/extensions/pkg/webhook/cmd"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/component-base/version/verflag"
@@ -23,6 +24,8 @@ import (
 	gvisorcontroller "github.com/gardener/gardener-extension-runtime-gvisor/pkg/controller"
 	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
 	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/healthcheck"
+	gvisorwebhookcmd "github.com/gardener/gardener-extension-runtime-gvisor/pkg/webhook/cmd"
+	oscwebhook "github.com/gardener/gardener-extension-runtime-gvisor/pkg/webhook/operatingsystemconfig"
 )
 
 // NewControllerManagerCommand creates a new command that is used to start the Container runtime gvisor controller.
@@ -34,6 +37,8 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 			LeaderElection:          true,
 			LeaderElectionID:        controllercmd.LeaderElectionNameID(gvisor.Name),
 			LeaderElectionNamespace: os.Getenv("LEADER_ELECTION_NAMESPACE"),
+			WebhookServerPort:       443,
+			WebhookCertDir:          "/tmp/gardener-extensions-cert",
 		}
 		reconcileOpts = &controllercmd.ReconcilerOptions{
 			IgnoreOperationAnnotation: true,
@@ -58,6 +63,20 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 			Namespace:            os.Getenv("LEADER_ELECTION_NAMESPACE"),
 		}
 
+		// options for the webhook server
+		webhookServerOptions = &webhookcmd.ServerOptions{
+			Namespace: os.Getenv("WEBHOOK_CONFIG_NAMESPACE"),
+		}
+		webhookSwitches = gvisorwebhookcmd.WebhookSwitchOptions()
+		webhookOptions  = webhookcmd.NewAddToManagerOptions(
+			gvisor.Name,
+			"",
+			nil,
+			nil,
+			webhookServerOptions,
+			webhookSwitches,
+		)
+
 		aggOption = controllercmd.NewOptionAggregator(
 			generalOpts,
 			restOpts,
@@ -67,6 +86,7 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 			controllercmd.PrefixOption("healthcheck-", healthCheckCtrlOpts),
 			controllercmd.PrefixOption("heartbeat-", heartbeatCtrlOpts),
 			reconcileOpts,
+			webhookOptions,
 		)
 	)
 
@@ -108,6 +128,12 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 			gvisorCtrlOpts.Completed().Apply(&gvisorcontroller.DefaultAddOptions.Controller)
 			gvisorConfigOpts.Completed().Apply(&gvisorcontroller.DefaultAddOptions.Config)
 			heartbeatCtrlOpts.Completed().Apply(&heartbeat.DefaultAddOptions)
+			gvisorConfigOpts.Completed().Apply(&oscwebhook.DefaultAddOptions.Config)
+			gvisorConfigOpts.Completed().Apply(&healthcheck.Config)
+
+			if _, err := webhookOptions.Completed().AddToManager(ctx, mgr, nil); err != nil {
+				return fmt.Errorf("could not add webhooks to manager: %w", err)
+			}
 
 			if err := gvisorcontroller.AddToManager(ctx, mgr); err != nil {
 				return fmt.Errorf("could not add controllers to manager: %w", err)
@@ -0,0 +1,375 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/component-base/version/verflag"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/nodeinstaller"
+)
+
+const (
+	// Name is the name of the node installer.
+	Name = "gvisor-node-installer"
+
+	completedMarkerPath = "/tmp/completed"
+)
+
+type options struct {
+	hostRoot              string
+	contentDir            string
+	binDir                string
+	runscConfigFlagsFile  string
+	runscProfilesFile     string
+	platform              string
+	containerdConfigMode  string
+	nodeName              string
+	uninstall             bool
+	runscConfigOnly       bool
+	removeStartupTaint    bool
+	haltOnFailure         bool
+	drainBeforeRestart    bool
+	maxConcurrentRestarts int
+	workerPool            string
+	repairInterval        time.Duration
+}
+
+func (o *options) addFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.hostRoot, "host-root", "/var/host", "path at which the root filesystem of the node is mounted")
+	fs.StringVar(&o.contentDir, "content-dir", "/var/content", "directory containing the binaries to install")
+	fs.StringVar(&o.binDir, "bin-dir", "", "directory on the node into which the binaries are installed")
+	fs.StringVar(&o.runscConfigFlagsFile, "runsc-config-flags-file", "", "file containing the flags of the [runsc_config] section of runsc.toml")
+	fs.StringVar(&o.runscProfilesFile, "runsc-profiles-file", "", "YAML file containing the flags of the [runsc_config] section of the runsc configs of the profiles by their name")
+	fs.StringVar(&o.platform, "platform", string(config.PlatformSystrap), "gVisor platform, one of systrap, ptrace, kvm or auto")
+	fs.StringVar(&o.containerdConfigMode, "containerd-config-mode", string(gvisor.ContainerdConfigModeAuto), "mode in which the runsc runtime is configured in containerd, one of auto, drop-in or config-file")
+	fs.StringVar(&o.nodeName, "node-name", os.Getenv("NODE_NAME"), "name of the node, used to report the installation state as node labels")
+	fs.BoolVar(&o.uninstall, "uninstall", false, "remove gVisor from the node instead of installing it")
+	fs.BoolVar(&o.runscConfigOnly, "runsc-config-only", false, "only write the runsc config and exit, used if gVisor is installed via the OperatingSystemConfig")
+	fs.BoolVar(&o.removeStartupTaint, "remove-startup-taint", false, "start a test sandbox after the installation and remove the startup taint from the node afterwards")
+	fs.BoolVar(&o.drainBeforeRestart, "drain-before-restart", false, "cordon and drain the node before containerd is restarted and uncordon it afterwards")
+	fs.IntVar(&o.maxConcurrentRestarts, "max-concurrent-restarts", 1, "maximum number of nodes of the worker pool which are drained and restart containerd at the same time")
+	fs.StringVar(&o.workerPool, "worker-pool", "", "name of the worker pool of the node, used to limit the number of concurrent containerd restarts")
+	fs.DurationVar(&o.repairInterval, "repair-interval", 5*time.Minute, "interval in which the installation is verified and drifted binaries and config files are repaired, changes of the files are repaired immediately, 0 disables repairs")
+	fs.BoolVar(&o.haltOnFailure, "halt-on-failure", true, "fail if the installation cannot be verified, which halts the rollout of the DaemonSet, instead of only marking the node as not ready")
+}
+
+func (o *options) validate() error {
+	if o.binDir == "" && !o.runscConfigOnly {
+		return fmt.Errorf("--bin-dir must be set")
+	}
+
+	if o.uninstall && o.runscConfigOnly {
+		return fmt.Errorf("--uninstall and --runsc-config-only are mutually exclusive")
+	}
+
+	if o.removeStartupTaint && (o.uninstall || o.runscConfigOnly) {
+		return fmt.Errorf("--remove-startup-taint can only be used for installations")
+	}
+
+	if o.removeStartupTaint && o.nodeName == "" {
+		return fmt.Errorf("--node-name must be set to remove the startup taint")
+	}
+
+	if o.drainBeforeRestart && (o.nodeName == "" || o.workerPool == "") {
+		return fmt.Errorf("--node-name and --worker-pool must be set to drain the node")
+	}
+
+	if o.repairInterval < 0 {
+		return fmt.Errorf("--repair-interval must not be negative")
+	}
+
+	if o.maxConcurrentRestarts < 1 {
+		return fmt.Errorf("--max-concurrent-restarts must be greater than 0")
+	}
+
+	switch config.Platform(o.platform) {
+	case config.PlatformSystrap, config.PlatformPtrace, config.PlatformKVM, config.PlatformAuto:
+	default:
+		return fmt.Errorf("unsupported platform %q", o.platform)
+	}
+
+	switch gvisor.ContainerdConfigMode(o.containerdConfigMode) {
+	case gvisor.ContainerdConfigModeAuto, gvisor.ContainerdConfigModeDropIn, gvisor.ContainerdConfigModeConfigFile:
+	default:
+		return fmt.Errorf("unsupported containerd config mode %q", o.containerdConfigMode)
+	}
+
+	return nil
+}
+
+// NewNodeInstallerCommand creates a new command that installs gVisor on the node it is running on or removes it from
+// the node.
+func NewNodeInstallerCommand(ctx context.Context) *cobra.Command {
+	opts := &options{}
+
+	cmd := &cobra.Command{
+		Use: Name,
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+			// Act on version flag, if one was specified
+			verflag.PrintAndExitIfRequested()
+
+			if err := opts.validate(); err != nil {
+				return err
+			}
+
+			return run(ctx, opts)
+		},
+	}
+
+	verflag.AddFlags(cmd.Flags())
+	opts.addFlags(cmd.Flags())
+
+	return cmd
+}
+
+func run(ctx context.Context, opts *options) error {
+	log := runtimelog.Log.WithName(Name)
+
+	var runscConfigFlags []byte
+	if opts.runscConfigFlagsFile != "" {
+		var err error
+		if runscConfigFlags, err = os.ReadFile(opts.runscConfigFlagsFile); err != nil {
+			return fmt.Errorf("could not read runsc config flags: %w", err)
+		}
+	}
+
+	var profiles map[string]string
+	if opts.runscProfilesFile != "" {
+		content, err := os.ReadFile(opts.runscProfilesFile)
+		if err != nil {
+			return fmt.Errorf("could not read runsc profiles: %w", err)
+		}
+		if err := yaml.Unmarshal(content, &profiles); err != nil {
+			return fmt.Errorf("could not decode runsc profiles: %w", err)
+		}
+	}
+
+	installerOpts := nodeinstaller.Options{
+		HostRoot:             opts.hostRoot,
+		ContentDir:           opts.contentDir,
+		BinDir:               opts.binDir,
+		RunscConfigFlags:     string(runscConfigFlags),
+		Profiles:             profiles,
+		Platform:             config.Platform(opts.platform),
+		ContainerdConfigMode: gvisor.ContainerdConfigMode(opts.containerdConfigMode),
+		TestSandbox:          opts.removeStartupTaint,
+	}
+	if opts.drainBeforeRestart {
+		c, err := newClient()
+		if err != nil {
+			return fmt.Errorf("failed creating client for draining the node: %w", err)
+		}
+		installerOpts.RestartCoordinator = nodeinstaller.NewNodeDrainer(log, c, opts.nodeName, opts.workerPool, opts.maxConcurrentRestarts)
+	}
+	if opts.nodeName != "" && !opts.uninstall && !opts.runscConfigOnly {
+		c, err := newClient()
+		if err != nil {
+			return fmt.Errorf("failed creating client for reporting modified binaries: %w", err)
+		}
+		installerOpts.TamperReporter = nodeinstaller.NewNodeTamperReporter(c, opts.nodeName)
+	}
+	installer := nodeinstaller.New(log, installerOpts)
+
+	if opts.runscConfigOnly {
+		result, err := installer.ConfigureRunsc()
+		if err != nil {
+			return err
+		}
+
+		log.Info("Configured runsc", "platform", result.Platform)
+		return nil
+	}
+
+	if opts.uninstall {
+		if _, err := installer.Uninstall(ctx); err != nil {
+			return err
+		}
+
+		if opts.nodeName != "" {
+			if err := updateNode(ctx, func(c client.Client) error {
+				labels, err := nodeinstaller.ProfileLabels(ctx, c, opts.nodeName, nil)
+				if err != nil {
+					return err
+				}
+				labels[gvisor.LabelPlatform] = nil
+				labels[gvisor.LabelReady] = nil
+
+				if err := nodeinstaller.PatchNode(ctx, c, opts.nodeName, labels,
+					map[string]*string{gvisor.AnnotationRunscVersion: nil, gvisor.AnnotationConfigHash: nil, gvisor.AnnotationInstallationError: nil, gvisor.AnnotationDriftCount: nil, gvisor.AnnotationOutdatedSandboxes: nil, gvisor.AnnotationTamperedBinaries: nil},
+				); err != nil {
+					return err
+				}
+				// Nodes which have been registered with the startup taint must not stay unschedulable.
+				return nodeinstaller.RemoveNodeTaint(ctx, c, opts.nodeName, gvisor.TaintKeyNotReady)
+			}); err != nil {
+				log.Error(err, "Failed removing installation state from node", "node", opts.nodeName)
+			}
+		}
+	} else {
+		result, err := installer.Install(ctx)
+		if err := reportInstallation(ctx, log, opts, result, err, opts.haltOnFailure); err != nil {
+			return err
+		}
+		if err != nil {
+			// The node stays marked as not ready (and keeps the startup taint), but the rollout continues with the next
+			// node.
+			log.Error(err, "Installation failed, continuing rollout")
+		}
+
+		if opts.repairInterval > 0 {
+			return repair(ctx, log, opts, installer, result)
+		}
+	}
+
+	if err := markCompleted(); err != nil {
+		return err
+	}
+
+	log.Info("Completed, sleeping")
+	<-ctx.Done()
+	return nil
+}
+
+// repair repairs drift of the installation until the context is cancelled. Repairs are counted on the node and their
+// result is reported like the result of the installation. Failed repairs do not halt the installer, as the rollout of
+// the DaemonSet has already passed the node.
+func repair(ctx context.Context, log logr.Logger, opts *options, installer *nodeinstaller.Installer, lastResult *nodeinstaller.Result) error {
+	repairer, err := nodeinstaller.NewRepairer(installer)
+	if err != nil {
+		return err
+	}
+
+	if err := markCompleted(); err != nil {
+		return err
+	}
+
+	log.Info("Completed, repairing drift", "interval", opts.repairInterval)
+	repairer.Run(ctx, opts.repairInterval, func(result *nodeinstaller.Result, err error) {
+		// The node is only updated if the state of the installation changed. Outdated sandboxes disappear once their pods
+		// are restarted.
+		if err == nil && len(result.Changed) == 0 && lastResult != nil && result.OutdatedSandboxes == lastResult.OutdatedSandboxes {
+			return
+		}
+		lastResult = result
+
+		if err == nil && len(result.Changed) > 0 && opts.nodeName != "" {
+			if err := updateNode(ctx, func(c client.Client) error {
+				count, err := nodeinstaller.IncrementNodeAnnotation(ctx, c, opts.nodeName, gvisor.AnnotationDriftCount)
+				if err == nil {
+					log.Info("Counted drift of installation", "node", opts.nodeName, "count", count)
+				}
+				return err
+			}); err != nil {
+				log.Error(err, "Failed counting drift of installation", "node", opts.nodeName)
+			}
+		}
+
+		if err := reportInstallation(ctx, log, opts, result, err, false); err != nil {
+			log.Error(err, "Failed reporting repaired installation")
+		}
+	})
+	return nil
+}
+
+// reportInstallation reports the result of an installation as labels and annotations on the node. The error of a failed
+// installation is returned if haltOnFailure is true.
+func reportInstallation(ctx context.Context, log logr.Logger, opts *options, result *nodeinstaller.Result, err error, haltOnFailure bool) error {
+	if err != nil {
+		// Sandboxes must not be scheduled to the node until the installation succeeds. The error marks the node as
+		// failed in the health checks of the extension.
+		if opts.nodeName != "" {
+			if err := updateNode(ctx, func(c client.Client) error {
+				return nodeinstaller.PatchNode(ctx, c, opts.nodeName,
+					map[string]*string{gvisor.LabelReady: nil},
+					map[string]*string{gvisor.AnnotationInstallationError: ptr.To(err.Error())},
+				)
+			}); err != nil {
+				log.Error(err, "Failed marking node as failed", "node", opts.nodeName)
+			}
+		}
+		// The pod doesn't become ready, hence the DaemonSet controller doesn't update further nodes.
+		if haltOnFailure {
+			return err
+		}
+		return nil
+	}
+
+	if opts.nodeName == "" {
+		return nil
+	}
+	if err := updateNode(ctx, func(c client.Client) error {
+		// The RuntimeClasses of the profiles only select nodes on which the profiles are configured.
+		labels, err := nodeinstaller.ProfileLabels(ctx, c, opts.nodeName, result.Profiles)
+		if err != nil {
+			return err
+		}
+		labels[gvisor.LabelPlatform] = ptr.To(string(result.Platform))
+		labels[gvisor.LabelReady] = ptr.To("true")
+
+		if err := nodeinstaller.PatchNode(ctx, c, opts.nodeName, labels,
+			map[string]*string{
+				gvisor.AnnotationRunscVersion:      &result.RunscVersion,
+				gvisor.AnnotationConfigHash:        &result.ConfigHash,
+				gvisor.AnnotationOutdatedSandboxes: ptr.To(strconv.Itoa(result.OutdatedSandboxes)),
+				gvisor.AnnotationInstallationError: nil,
+			},
+		); err != nil {
+			return err
+		}
+		if opts.removeStartupTaint {
+			return nodeinstaller.RemoveNodeTaint(ctx, c, opts.nodeName, gvisor.TaintKeyNotReady)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed reporting installation state on node %s: %w", opts.nodeName, err)
+	}
+	return nil
+}
+
+// markCompleted writes the marker which is used by the readiness probe of the DaemonSets to signal that the node has
+// been handled.
+func markCompleted() error {
+	if err := os.WriteFile(completedMarkerPath, nil, 0600); err != nil {
+		return fmt.Errorf("could not write completed marker: %w", err)
+	}
+	return nil
+}
+
+// updateNode calls the given function with a client for the cluster the node installer is running in.
+func updateNode(ctx context.Context, update func(client.Client) error) error {
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	return update(c)
+}
+
+// newClient creates a client for the cluster the node installer is running in.
+func newClient() (client.Client, error) {
+	restConfig, err := ctrlconfig.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.New(restConfig, client.Options{})
+}
@@ -15,6 +15,9 @@ wget "${URL}/runsc" "${URL}/runsc.sha512" \
     "${URL}/containerd-shim-runsc-v1" "${URL}/containerd-shim-runsc-v1.sha512"
 sha512sum -c runsc.sha512 \
     -c containerd-shim-runsc-v1.sha512
+# The checksums are shipped in the installation image, the node installer verifies the binaries against them.
+mkdir -p /usr/local/share/gvisor
+cat runsc.sha512 containerd-shim-runsc-v1.sha512 > /usr/local/share/gvisor/SHA512SUMS
 rm -f -- *.sha512
 chmod a+rx runsc containerd-shim-runsc-v1
 mv runsc containerd-shim-runsc-v1 /usr/local/bin
@@ -6,10 +6,13 @@ package imagevector
 
 import (
 	_ "embed"
+	"fmt"
+	"slices"
 
 	"github.com/gardener/gardener/pkg/utils/imagevector"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/component-base/version"
+	"k8s.io/utils/ptr"
 
 	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
 )
@@ -22,16 +25,28 @@ var (
 )
 
 func init() {
-	var err error
-
-	imageVector, caBundle, err = imagevector.Read([]byte(ImagesYAML))
+	images, bundle, err := imagevector.Read([]byte(ImagesYAML))
 	runtime.Must(err)
+
+	// The override merges images with the same name regardless of their version attribute, hence it is only applied
+	// to the default images.
+	var defaultImages, versionedImages imagevector.ImageVector
+	for _, source := range images {
+		if source.Version == nil {
+			defaultImages = append(defaultImages, source)
+		} else {
+			versionedImages = append(versionedImages, source)
+		}
+	}
+
 	// image vector for components deployed by the gVisor extension
-	imageVector, caBundle, err = imagevector.WithEnvOverride(imageVector, caBundle, imagevector.OverrideEnv)
+	defaultImages, caBundle, err = imagevector.WithEnvOverride(defaultImages, bundle, imagevector.OverrideEnv)
 	runtime.Must(err)
+	imageVector = append(defaultImages, versionedImages...)
 
-	_, err = imageVector.FindImage(gvisor.RuntimeGVisorInstallationImageName)
-	runtime.Must(err)
+	if findImageSource(gvisor.RuntimeGVisorInstallationImageName, "") == nil {
+		runtime.Must(fmt.Errorf("could not find default image %q", gvisor.RuntimeGVisorInstallationImageName))
+	}
 }
 
 // ImageVector is the image vector that contains all the needed images.
@@ -39,10 +54,16 @@ func ImageVector() imagevector.ImageVector {
 	return imageVector
 }
 
-// FindImage returns the container runtime GVisor image.
-func FindImage(name string) string {
-	image, err := imageVector.FindImage(name)
-	runtime.Must(err)
+// FindImage returns the image with the given name and version attribute, e.g. the installation image containing a
+// particular gVisor version. The default image, i.e. the first image with the given name without version attribute,
+// is returned if the version is empty or the version of the default image. Images without tag are tagged with the
+// version of the extension.
+func FindImage(name, imageVersion string) string {
+	source := findImageSource(name, imageVersion)
+	if source == nil {
+		runtime.Must(fmt.Errorf("could not find image %q with version %q", name, imageVersion))
+	}
+	image := source.ToImage(nil)
 
 	var (
 		repository = image.String()
@@ -58,3 +79,40 @@ func FindImage(name string) string {
 	}
 	return calculatedImage.String()
 }
+
+// Versions returns the versions of the images with the given name. The version of the default image is only contained
+// if it is known, see DefaultVersion.
+func Versions(name string) []string {
+	var versions []string
+	if defaultVersion := DefaultVersion(name); defaultVersion != "" && findImageSource(name, "") != nil {
+		versions = append(versions, defaultVersion)
+	}
+	for _, source := range imageVector {
+		if source.Name == name && source.Version != nil && !slices.Contains(versions, *source.Version) {
+			versions = append(versions, *source.Version)
+		}
+	}
+	return versions
+}
+
+// DefaultVersion returns the version of the default image with the given name. Only the version of the default
+// installation image is known, it is the gVisor version the extension has been built with.
+func DefaultVersion(name string) string {
+	if name == gvisor.RuntimeGVisorInstallationImageName {
+		return gvisor.Version
+	}
+	return ""
+}
+
+func findImageSource(name, imageVersion string) *imagevector.ImageSource {
+	if imageVersion == DefaultVersion(name) {
+		imageVersion = ""
+	}
+
+	for _, source := range imageVector {
+		if source.Name == name && ptr.Deref(source.Version, "") == imageVersion {
+			return source
+		}
+	}
+	return nil
+}
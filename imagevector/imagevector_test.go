@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package imagevector
+
+import (
+	"github.com/gardener/gardener/pkg/utils/imagevector"
+	"github.com/gardener/gardener/pkg/utils/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/component-base/version"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+)
+
+var _ = Describe("ImageVector", func() {
+	const (
+		name       = "runtime-gvisor-installation"
+		repository = "example.com/runtime-gvisor-installation"
+	)
+
+	BeforeEach(func() {
+		DeferCleanup(test.WithVars(
+			&gvisor.Version, "20260810.0",
+			&imageVector, imagevector.ImageVector{
+				{Name: "other", Repository: ptr.To("example.com/other"), Version: ptr.To("20260101.0")},
+				{Name: name, Repository: ptr.To(repository)},
+				{Name: name, Repository: ptr.To(repository), Tag: ptr.To("v0.40.0"), Version: ptr.To("20260601.0")},
+				{Name: name, Repository: ptr.To(repository), Tag: ptr.To("v0.38.0"), Version: ptr.To("20260401.0")},
+			},
+		))
+	})
+
+	Describe("#FindImage", func() {
+		It("should return the default image if no version is given", func() {
+			Expect(FindImage(name, "")).To(Equal(repository + ":" + version.Get().GitVersion))
+		})
+
+		It("should return the default image for its version", func() {
+			Expect(FindImage(name, "20260810.0")).To(Equal(repository + ":" + version.Get().GitVersion))
+		})
+
+		It("should return the image with the given version", func() {
+			Expect(FindImage(name, "20260401.0")).To(Equal(repository + ":v0.38.0"))
+		})
+
+		It("should panic if there is no image with the given version", func() {
+			Expect(func() { FindImage(name, "20260101.0") }).To(Panic())
+		})
+	})
+
+	Describe("#Versions", func() {
+		It("should return the versions of the images with the given name", func() {
+			Expect(Versions(name)).To(Equal([]string{"20260810.0", "20260601.0", "20260401.0"}))
+		})
+
+		It("should not return the version of the default image if it is unknown", func() {
+			gvisor.Version = ""
+
+			Expect(Versions(name)).To(Equal([]string{"20260601.0", "20260401.0"}))
+		})
+	})
+})
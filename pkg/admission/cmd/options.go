@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	webhookcmd "github.com/gardener/gardener/extensions/pkg/webhook/cmd"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/admission/validator"
+)
+
+// GardenWebhookSwitchOptions are the webhookcmd.SwitchOptions for the admission webhooks.
+func GardenWebhookSwitchOptions() *webhookcmd.SwitchOptions {
+	return webhookcmd.NewSwitchOptions(
+		webhookcmd.Switch(validator.Name, validator.New),
+	)
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	"github.com/gardener/gardener/pkg/apis/core"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/validation"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+)
+
+type shoot struct {
+	decoder runtime.Decoder
+}
+
+// NewShootValidator returns a new instance of a shoot validator.
+func NewShootValidator(mgr manager.Manager) extensionswebhook.Validator {
+	return &shoot{
+		decoder: serializer.NewCodecFactory(mgr.GetScheme(), serializer.EnableStrict).UniversalDecoder(),
+	}
+}
+
+// Validate validates the gVisor provider config of all container runtimes of the given shoot.
+func (s *shoot) Validate(_ context.Context, newObj, oldObj client.Object) error {
+	shoot, ok := newObj.(*core.Shoot)
+	if !ok {
+		return fmt.Errorf("wrong object type %T", newObj)
+	}
+
+	if shoot.DeletionTimestamp != nil {
+		return nil
+	}
+
+	var oldShoot *core.Shoot
+	if oldObj != nil {
+		if oldShoot, ok = oldObj.(*core.Shoot); !ok {
+			return fmt.Errorf("wrong object type %T for old object", oldObj)
+		}
+	}
+
+	return s.validateShoot(shoot, oldShoot).ToAggregate()
+}
+
+func (s *shoot) validateShoot(shoot, oldShoot *core.Shoot) field.ErrorList {
+	allErrs := field.ErrorList{}
+	workersPath := field.NewPath("spec", "provider", "workers")
+
+	oldVersions := s.gvisorVersions(oldShoot)
+
+	for i, worker := range shoot.Spec.Provider.Workers {
+		if worker.CRI == nil {
+			continue
+		}
+
+		for j, containerRuntime := range worker.CRI.ContainerRuntimes {
+			if containerRuntime.Type != gvisor.Type || containerRuntime.ProviderConfig == nil {
+				continue
+			}
+
+			providerConfigPath := workersPath.Index(i).Child("cri", "containerRuntimes").Index(j).Child("providerConfig")
+
+			gvisorConfig := &config.GVisorConfiguration{}
+			if _, _, err := s.decoder.Decode(containerRuntime.ProviderConfig.Raw, nil, gvisorConfig); err != nil {
+				allErrs = append(allErrs, field.Invalid(providerConfigPath, string(containerRuntime.ProviderConfig.Raw), fmt.Sprintf("could not decode provider config: %v", err)))
+				continue
+			}
+
+			allErrs = append(allErrs, validation.ValidateGVisorConfiguration(gvisorConfig, providerConfigPath)...)
+			allErrs = append(allErrs, validation.ValidateGVisorVersion(gvisorConfig.Version, oldVersions[worker.Name], providerConfigPath.Child("version"))...)
+		}
+	}
+
+	return allErrs
+}
+
+// gvisorVersions returns the gVisor versions configured for the worker pools of the given shoot by their name.
+func (s *shoot) gvisorVersions(shoot *core.Shoot) map[string]*string {
+	versions := map[string]*string{}
+	if shoot == nil {
+		return versions
+	}
+
+	for _, worker := range shoot.Spec.Provider.Workers {
+		if worker.CRI == nil {
+			continue
+		}
+
+		for _, containerRuntime := range worker.CRI.ContainerRuntimes {
+			if containerRuntime.Type != gvisor.Type || containerRuntime.ProviderConfig == nil {
+				continue
+			}
+
+			gvisorConfig := &config.GVisorConfiguration{}
+			if _, _, err := s.decoder.Decode(containerRuntime.ProviderConfig.Raw, nil, gvisorConfig); err != nil {
+				// The version of an old provider config which cannot be decoded is treated as newly set.
+				continue
+			}
+			versions[worker.Name] = gvisorConfig.Version
+		}
+	}
+
+	return versions
+}
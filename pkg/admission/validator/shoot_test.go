@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validator_test
+
+import (
+	"context"
+
+	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	"github.com/gardener/gardener/pkg/apis/core"
+	"github.com/gardener/gardener/pkg/utils/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/admission/validator"
+	gvisorinstall "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/install"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+)
+
+type fakeManager struct {
+	manager.Manager
+	scheme *runtime.Scheme
+}
+
+func (m *fakeManager) GetScheme() *runtime.Scheme {
+	return m.scheme
+}
+
+var _ = Describe("Shoot validator", func() {
+	var (
+		ctx = context.Background()
+
+		shootValidator extensionswebhook.Validator
+		shoot          *core.Shoot
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		gvisorinstall.Install(scheme)
+		shootValidator = validator.NewShootValidator(&fakeManager{scheme: scheme})
+
+		shoot = &core.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: "shoot", Namespace: "garden-dev"},
+			Spec: core.ShootSpec{
+				Provider: core.Provider{
+					Workers: []core.Worker{
+						{
+							Name: "worker-1",
+							CRI: &core.CRI{
+								Name:              core.CRINameContainerD,
+								ContainerRuntimes: []core.ContainerRuntime{{Type: "gvisor"}},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	setProviderConfig := func(containerRuntimeType, providerConfig string) {
+		shoot.Spec.Provider.Workers[0].CRI.ContainerRuntimes = []core.ContainerRuntime{{
+			Type:           containerRuntimeType,
+			ProviderConfig: &runtime.RawExtension{Raw: []byte(providerConfig)},
+		}}
+	}
+
+	It("should return an error for unexpected object types", func() {
+		Expect(shootValidator.Validate(ctx, &corev1.Pod{}, nil)).To(MatchError(ContainSubstring("wrong object type")))
+	})
+
+	It("should allow a shoot without provider config", func() {
+		Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+	})
+
+	It("should allow a shoot without CRI configuration", func() {
+		shoot.Spec.Provider.Workers[0].CRI = nil
+
+		Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+	})
+
+	It("should allow a valid provider config", func() {
+		setProviderConfig("gvisor", `{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","netRaw":true,"network":"host"}`)
+
+		Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+	})
+
+	It("should allow a valid deprecated provider config", func() {
+		setProviderConfig("gvisor", `{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1alpha1","kind":"GVisorConfiguration","configFlags":{"net-raw":"true"}}`)
+
+		Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+	})
+
+	It("should ignore provider configs of other container runtimes", func() {
+		setProviderConfig("kata", `{"foo":"bar"}`)
+
+		Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+	})
+
+	It("should forbid unknown fields", func() {
+		setProviderConfig("gvisor", `{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","netraw":true}`)
+
+		Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(And(
+			ContainSubstring("spec.provider.workers[0].cri.containerRuntimes[0].providerConfig"),
+			ContainSubstring(`unknown field "netraw"`),
+		)))
+	})
+
+	It("should forbid unknown API versions", func() {
+		setProviderConfig("gvisor", `{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1","kind":"GVisorConfiguration"}`)
+
+		Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring(`no kind "GVisorConfiguration" is registered`)))
+	})
+
+	It("should forbid invalid values", func() {
+		setProviderConfig("gvisor", `{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1alpha1","kind":"GVisorConfiguration","configFlags":{"net-raw":"yes"}}`)
+
+		Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring(`spec.provider.workers[0].cri.containerRuntimes[0].providerConfig.configFlags[net-raw]: Invalid value: "yes"`)))
+	})
+
+	Describe("version", func() {
+		const removedVersion = `{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","version":"20200101.0"}`
+
+		BeforeEach(func() {
+			DeferCleanup(test.WithVar(&gvisor.Version, "20260810.0"))
+		})
+
+		It("should allow the default version", func() {
+			setProviderConfig("gvisor", `{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","version":"20260810.0"}`)
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+		})
+
+		It("should forbid versions which are not offered on creation", func() {
+			setProviderConfig("gvisor", removedVersion)
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring(`spec.provider.workers[0].cri.containerRuntimes[0].providerConfig.version: Unsupported value: "20200101.0"`)))
+		})
+
+		It("should forbid changing the version to one which is not offered", func() {
+			oldShoot := shoot.DeepCopy()
+			setProviderConfig("gvisor", removedVersion)
+
+			Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(ContainSubstring(`Unsupported value: "20200101.0"`)))
+		})
+
+		It("should allow updating shoots which use a version which is not offered anymore", func() {
+			setProviderConfig("gvisor", removedVersion)
+			oldShoot := shoot.DeepCopy()
+			shoot.Spec.Provider.Workers[0].Minimum = 3
+
+			Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(Succeed())
+		})
+
+		It("should forbid using a version which is not offered anymore for another worker pool", func() {
+			setProviderConfig("gvisor", removedVersion)
+			oldShoot := shoot.DeepCopy()
+			worker := *shoot.Spec.Provider.Workers[0].DeepCopy()
+			worker.Name = "worker-2"
+			shoot.Spec.Provider.Workers = append(shoot.Spec.Provider.Workers, worker)
+
+			Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(ContainSubstring(`spec.provider.workers[1].cri.containerRuntimes[0].providerConfig.version: Unsupported value: "20200101.0"`)))
+		})
+	})
+
+	It("should not validate shoots in deletion", func() {
+		setProviderConfig("gvisor", `{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1alpha1","kind":"GVisorConfiguration","configFlags":{"net-raw":"yes"}}`)
+		shoot.DeletionTimestamp = &metav1.Time{}
+
+		Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+	})
+})
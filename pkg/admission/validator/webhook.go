@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validator
+
+import (
+	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	"github.com/gardener/gardener/pkg/apis/core"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+)
+
+const (
+	// Name is a name for a validation webhook.
+	Name = "validator"
+)
+
+var logger = log.Log.WithName("gvisor-validator-webhook")
+
+// New creates a new webhook that validates Shoot resources.
+func New(mgr manager.Manager) (*extensionswebhook.Webhook, error) {
+	logger.Info("Setting up webhook", "name", Name)
+
+	return extensionswebhook.New(mgr, extensionswebhook.Args{
+		Name:   Name,
+		Path:   "/webhooks/validate",
+		Target: extensionswebhook.TargetSeed,
+		Validators: map[extensionswebhook.Validator][]extensionswebhook.Type{
+			NewShootValidator(mgr): {{Obj: &core.Shoot{}}},
+		},
+		ObjectSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{v1beta1constants.LabelExtensionContainerRuntimeTypePrefix + gvisor.Type: "true"},
+		},
+	})
+}
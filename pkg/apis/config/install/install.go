@@ -10,11 +10,13 @@ import (
 
 	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
 	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/v1alpha1"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/v1beta1"
 )
 
 var (
 	schemeBuilder = runtime.NewSchemeBuilder(
 		v1alpha1.AddToScheme,
+		v1beta1.AddToScheme,
 		config.AddToScheme,
 		setVersionPriority,
 	)
@@ -24,7 +26,7 @@ var (
 )
 
 func setVersionPriority(scheme *runtime.Scheme) error {
-	return scheme.SetVersionPriority(v1alpha1.SchemeGroupVersion)
+	return scheme.SetVersionPriority(v1beta1.SchemeGroupVersion, v1alpha1.SchemeGroupVersion)
 }
 
 // Install installs all APIs in the scheme.
@@ -4,7 +4,10 @@
 
 package config
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -13,11 +16,193 @@ type GVisorConfiguration struct {
 	metav1.TypeMeta
 
 	// ConfigFlags is a map of additional flags that are passed to the runsc binary.
+	// It is only set when the configuration was provided in the deprecated free-form map representation. The known
+	// flags are converted into the typed fields, the map itself is kept for validation purposes only.
 	ConfigFlags *map[string]string
 
+	// Version is the version of gVisor installed on the nodes.
+	Version *string
+
+	// Platform is the platform runsc uses to intercept the system calls of the sandboxed applications.
+	Platform *Platform
+	// Network is the networking mode of the sandbox.
+	Network *NetworkMode
+	// Overlay is the overlay filesystem configuration of the sandbox.
+	Overlay *string
+	// FileAccess is the file access mode of the sandbox for the root filesystem.
+	FileAccess *FileAccessMode
+	// Debug enables debug logging of runsc.
+	Debug *bool
+	// DebugLog is the path of the runsc debug logs on the node.
+	DebugLog *string
+	// NetRaw enables raw sockets inside the sandbox.
+	NetRaw *bool
+	// NVProxy enables the proxy for NVIDIA GPU devices.
+	NVProxy *bool
+	// PanicSignal is the signal number that makes the sandbox panic and dump its stacks.
+	PanicSignal *int32
+
+	// StartupTaint makes new nodes of the worker pool start with a taint, which is removed once gVisor has been
+	// installed and verified on the node.
+	StartupTaint *bool
+
+	// Rollout configures how changes of the gVisor installation are rolled out to the nodes of the worker pool.
+	Rollout *Rollout
+
+	// Profiles are additional runsc configurations of the worker pool, each with its own runtime handler and
+	// RuntimeClass.
+	Profiles []Profile
+
 	// TestImageTag is the tag for the gardener-extension-runtime-gvisor-installation image to be tested.
 	// It requires that the `gvisorInstallation.testRepository` is configured in the operator extension values and
 	// the image has been uploaded and tagged accordingly.
 	// Only used for development and testing purposes. Does not work if `gvisorInstallation.testRepository` is not specified.
 	TestImageTag *string
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GVisorStatus contains information about the gVisor installation on the nodes of a worker pool.
+type GVisorStatus struct {
+	metav1.TypeMeta
+
+	// Version is the version of the gVisor release installed on the nodes.
+	Version string
+	// InstallationImage is the image containing the gVisor binaries installed on the nodes.
+	InstallationImage string
+	// RunscFlags are the effective flags of the `[runsc_config]` section of runsc.toml.
+	RunscFlags map[string]string
+	// ConfigHash is the hash of runsc.toml on the nodes.
+	ConfigHash string
+	// Platform is the platform configured for the nodes.
+	Platform Platform
+	// Profiles are the effective runsc flags of the profiles configured on the nodes.
+	Profiles []ProfileStatus
+	// PendingChange is a disruptive change of the installation which is deferred until the maintenance time window of
+	// the shoot.
+	PendingChange *PendingChange
+}
+
+// PendingChange is a change of the gVisor installation on the nodes which is deferred until the maintenance time
+// window of the shoot.
+type PendingChange struct {
+	// Version is the version of the gVisor release to be installed.
+	Version string
+	// InstallationImage is the image containing the gVisor binaries to be installed.
+	InstallationImage string
+	// Platform is the platform to be configured.
+	Platform Platform
+	// RunscFlags are the flags of the `[runsc_config]` section of runsc.toml to be configured.
+	RunscFlags map[string]string
+	// Profiles are the runsc flags of the profiles to be configured.
+	Profiles []ProfileStatus
+}
+
+// Profile is a named runsc configuration which is used by the pods with the RuntimeClass of the same name.
+type Profile struct {
+	// Name is the name of the profile and its RuntimeClass.
+	Name string
+	// Network is the networking mode of the sandbox.
+	Network *NetworkMode
+	// Overlay is the overlay filesystem configuration of the sandbox.
+	Overlay *string
+	// FileAccess is the file access mode of the sandbox for the root filesystem.
+	FileAccess *FileAccessMode
+	// Debug enables debug logging of runsc.
+	Debug *bool
+	// DebugLog is the path of the runsc debug logs on the node.
+	DebugLog *string
+	// NetRaw enables raw sockets inside the sandbox.
+	NetRaw *bool
+	// NVProxy enables the proxy for NVIDIA GPU devices.
+	NVProxy *bool
+	// PanicSignal is the signal number that makes the sandbox panic and dump its stacks.
+	PanicSignal *int32
+}
+
+// ProfileStatus contains the runsc flags of a profile.
+type ProfileStatus struct {
+	// Name is the name of the profile.
+	Name string
+	// RunscFlags are the flags of the `[runsc_config]` section of the runsc config of the profile.
+	RunscFlags map[string]string
+}
+
+// Rollout configures how changes of the gVisor installation are rolled out to the nodes of a worker pool.
+type Rollout struct {
+	// MaxUnavailable is the maximum number of nodes of the worker pool which are updated at the same time.
+	MaxUnavailable *intstr.IntOrString
+	// Pause is the duration the rollout waits after a node has been updated before the next node is updated.
+	Pause *metav1.Duration
+	// HaltOnFailure stops the rollout if the installation cannot be verified on a node.
+	HaltOnFailure *bool
+	// Drain cordons and drains the node before containerd is restarted.
+	Drain *bool
+	// MaxConcurrentRestarts is the maximum number of nodes of the worker pool which are drained and restart containerd
+	// at the same time.
+	MaxConcurrentRestarts *int32
+}
+
+// Platform is the platform of a gVisor sandbox.
+type Platform string
+
+const (
+	// PlatformSystrap intercepts system calls with seccomp traps. It works on virtual machines without nested
+	// virtualization and is the default of runsc.
+	PlatformSystrap Platform = "systrap"
+	// PlatformPtrace intercepts system calls with ptrace. It is deprecated in runsc and only kept for compatibility.
+	PlatformPtrace Platform = "ptrace"
+	// PlatformKVM uses hardware virtualization via /dev/kvm. It is the fastest platform on bare-metal nodes.
+	PlatformKVM Platform = "kvm"
+	// PlatformAuto lets the installation on the node decide between `kvm` and `systrap` depending on whether
+	// /dev/kvm is usable on the node.
+	PlatformAuto Platform = "auto"
+)
+
+// NetworkMode is the networking mode of a gVisor sandbox.
+type NetworkMode string
+
+const (
+	// NetworkModeSandbox uses the network stack of gVisor (netstack).
+	NetworkModeSandbox NetworkMode = "sandbox"
+	// NetworkModeHost uses the network stack of the host.
+	NetworkModeHost NetworkMode = "host"
+	// NetworkModeNone disables networking except for loopback.
+	NetworkModeNone NetworkMode = "none"
+)
+
+// FileAccessMode is the file access mode of a gVisor sandbox.
+type FileAccessMode string
+
+const (
+	// FileAccessModeExclusive assumes that the sandbox has exclusive access to the files.
+	FileAccessModeExclusive FileAccessMode = "exclusive"
+	// FileAccessModeShared allows files to be changed from outside the sandbox.
+	FileAccessModeShared FileAccessMode = "shared"
+)
+
+// DefaultDebugLog is the path of the runsc debug logs if debug logging is enabled and no other path is configured.
+const DefaultDebugLog = "/var/log/runsc/%ID%/gvisor-%COMMAND%.log"
+
+// Names of the runsc flags that are configurable via the GVisorConfiguration.
+// See https://github.com/google/gvisor/blob/master/runsc/config/flags.go for a list of all runsc flags.
+const (
+	// FlagPlatform is the runsc flag for the platform.
+	FlagPlatform = "platform"
+	// FlagNetwork is the runsc flag for the networking mode.
+	FlagNetwork = "network"
+	// FlagOverlay is the runsc flag for the overlay filesystem configuration.
+	FlagOverlay = "overlay2"
+	// FlagFileAccess is the runsc flag for the file access mode of the root filesystem.
+	FlagFileAccess = "file-access"
+	// FlagDebug is the runsc flag to enable debug logging.
+	FlagDebug = "debug"
+	// FlagDebugLog is the runsc flag for the path of the debug logs.
+	FlagDebugLog = "debug-log"
+	// FlagNetRaw is the runsc flag to enable raw sockets.
+	FlagNetRaw = "net-raw"
+	// FlagNVProxy is the runsc flag to enable the NVIDIA GPU proxy.
+	FlagNVProxy = "nvproxy"
+	// FlagPanicSignal is the runsc flag for the panic signal.
+	FlagPanicSignal = "panic-signal"
+)
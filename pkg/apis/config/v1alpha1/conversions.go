@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+)
+
+// Convert_v1alpha1_GVisorConfiguration_To_config_GVisorConfiguration converts the free-form config flags into the
+// typed fields of the internal configuration. Flags which are unknown or have an unparsable value are not converted.
+func Convert_v1alpha1_GVisorConfiguration_To_config_GVisorConfiguration(in *GVisorConfiguration, out *config.GVisorConfiguration, s conversion.Scope) error {
+	if err := autoConvert_v1alpha1_GVisorConfiguration_To_config_GVisorConfiguration(in, out, s); err != nil {
+		return err
+	}
+
+	if in.ConfigFlags == nil {
+		return nil
+	}
+
+	for key, value := range *in.ConfigFlags {
+		switch key {
+		case config.FlagPlatform:
+			out.Platform = ptr.To(config.Platform(value))
+		case config.FlagNetwork:
+			out.Network = ptr.To(config.NetworkMode(value))
+		case config.FlagOverlay:
+			out.Overlay = ptr.To(value)
+		case config.FlagFileAccess:
+			out.FileAccess = ptr.To(config.FileAccessMode(value))
+		case config.FlagDebug:
+			out.Debug = parseBool(value)
+		case config.FlagDebugLog:
+			out.DebugLog = ptr.To(value)
+		case config.FlagNetRaw:
+			out.NetRaw = parseBool(value)
+		case config.FlagNVProxy:
+			out.NVProxy = parseBool(value)
+		case config.FlagPanicSignal:
+			if signal, err := strconv.ParseInt(value, 10, 32); err == nil {
+				out.PanicSignal = ptr.To(int32(signal))
+			}
+		}
+	}
+
+	if ptr.Deref(out.Debug, false) && out.DebugLog == nil {
+		out.DebugLog = ptr.To(config.DefaultDebugLog)
+	}
+
+	return nil
+}
+
+// Convert_config_GVisorConfiguration_To_v1alpha1_GVisorConfiguration converts the typed fields of the internal
+// configuration into free-form config flags.
+func Convert_config_GVisorConfiguration_To_v1alpha1_GVisorConfiguration(in *config.GVisorConfiguration, out *GVisorConfiguration, s conversion.Scope) error {
+	if err := autoConvert_config_GVisorConfiguration_To_v1alpha1_GVisorConfiguration(in, out, s); err != nil {
+		return err
+	}
+
+	flags := map[string]string{}
+	if in.ConfigFlags != nil {
+		for key, value := range *in.ConfigFlags {
+			flags[key] = value
+		}
+	}
+
+	if in.Platform != nil {
+		flags[config.FlagPlatform] = string(*in.Platform)
+	}
+	if in.Network != nil {
+		flags[config.FlagNetwork] = string(*in.Network)
+	}
+	if in.Overlay != nil {
+		flags[config.FlagOverlay] = *in.Overlay
+	}
+	if in.FileAccess != nil {
+		flags[config.FlagFileAccess] = string(*in.FileAccess)
+	}
+	if in.Debug != nil {
+		flags[config.FlagDebug] = strconv.FormatBool(*in.Debug)
+	}
+	if in.DebugLog != nil {
+		flags[config.FlagDebugLog] = *in.DebugLog
+	}
+	if in.NetRaw != nil {
+		flags[config.FlagNetRaw] = strconv.FormatBool(*in.NetRaw)
+	}
+	if in.NVProxy != nil {
+		flags[config.FlagNVProxy] = strconv.FormatBool(*in.NVProxy)
+	}
+	if in.PanicSignal != nil {
+		flags[config.FlagPanicSignal] = strconv.Itoa(int(*in.PanicSignal))
+	}
+
+	out.ConfigFlags = nil
+	if len(flags) > 0 {
+		out.ConfigFlags = &flags
+	}
+
+	return nil
+}
+
+// parseBool only accepts the literal values "true" and "false", all other values are treated as invalid.
+func parseBool(value string) *bool {
+	switch value {
+	case "true":
+		return ptr.To(true)
+	case "false":
+		return ptr.To(false)
+	}
+	return nil
+}
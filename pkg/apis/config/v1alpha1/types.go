@@ -14,6 +14,7 @@ type GVisorConfiguration struct {
 	metav1.TypeMeta `json:",inline"`
 
 	// ConfigFlags is a map of additional flags that are passed to the runsc binary used by gVisor.
+	// Only the flags which are represented by typed fields in API version v1beta1 are taken into account.
 	// +optional
 	ConfigFlags *map[string]string `json:"configFlags,omitempty"`
 
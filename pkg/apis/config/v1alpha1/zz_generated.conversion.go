@@ -24,13 +24,13 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
-	if err := s.AddGeneratedConversionFunc((*GVisorConfiguration)(nil), (*config.GVisorConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha1_GVisorConfiguration_To_config_GVisorConfiguration(a.(*GVisorConfiguration), b.(*config.GVisorConfiguration), scope)
+	if err := s.AddConversionFunc((*config.GVisorConfiguration)(nil), (*GVisorConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_GVisorConfiguration_To_v1alpha1_GVisorConfiguration(a.(*config.GVisorConfiguration), b.(*GVisorConfiguration), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*config.GVisorConfiguration)(nil), (*GVisorConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_config_GVisorConfiguration_To_v1alpha1_GVisorConfiguration(a.(*config.GVisorConfiguration), b.(*GVisorConfiguration), scope)
+	if err := s.AddConversionFunc((*GVisorConfiguration)(nil), (*config.GVisorConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_GVisorConfiguration_To_config_GVisorConfiguration(a.(*GVisorConfiguration), b.(*config.GVisorConfiguration), scope)
 	}); err != nil {
 		return err
 	}
@@ -43,18 +43,21 @@ func autoConvert_v1alpha1_GVisorConfiguration_To_config_GVisorConfiguration(in *
 	return nil
 }
 
-// Convert_v1alpha1_GVisorConfiguration_To_config_GVisorConfiguration is an autogenerated conversion function.
-func Convert_v1alpha1_GVisorConfiguration_To_config_GVisorConfiguration(in *GVisorConfiguration, out *config.GVisorConfiguration, s conversion.Scope) error {
-	return autoConvert_v1alpha1_GVisorConfiguration_To_config_GVisorConfiguration(in, out, s)
-}
-
 func autoConvert_config_GVisorConfiguration_To_v1alpha1_GVisorConfiguration(in *config.GVisorConfiguration, out *GVisorConfiguration, s conversion.Scope) error {
 	out.ConfigFlags = (*map[string]string)(unsafe.Pointer(in.ConfigFlags))
+	// WARNING: in.Version requires manual conversion: does not exist in peer-type
+	// WARNING: in.Platform requires manual conversion: does not exist in peer-type
+	// WARNING: in.Network requires manual conversion: does not exist in peer-type
+	// WARNING: in.Overlay requires manual conversion: does not exist in peer-type
+	// WARNING: in.FileAccess requires manual conversion: does not exist in peer-type
+	// WARNING: in.Debug requires manual conversion: does not exist in peer-type
+	// WARNING: in.DebugLog requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetRaw requires manual conversion: does not exist in peer-type
+	// WARNING: in.NVProxy requires manual conversion: does not exist in peer-type
+	// WARNING: in.PanicSignal requires manual conversion: does not exist in peer-type
+	// WARNING: in.StartupTaint requires manual conversion: does not exist in peer-type
+	// WARNING: in.Rollout requires manual conversion: does not exist in peer-type
+	// WARNING: in.Profiles requires manual conversion: does not exist in peer-type
 	out.TestImageTag = (*string)(unsafe.Pointer(in.TestImageTag))
 	return nil
 }
-
-// Convert_config_GVisorConfiguration_To_v1alpha1_GVisorConfiguration is an autogenerated conversion function.
-func Convert_config_GVisorConfiguration_To_v1alpha1_GVisorConfiguration(in *config.GVisorConfiguration, out *GVisorConfiguration, s conversion.Scope) error {
-	return autoConvert_config_GVisorConfiguration_To_v1alpha1_GVisorConfiguration(in, out, s)
-}
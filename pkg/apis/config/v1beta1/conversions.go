@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/conversion"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+)
+
+// Convert_config_GVisorConfiguration_To_v1beta1_GVisorConfiguration drops the free-form config flags, the known flags
+// are already represented by the typed fields.
+func Convert_config_GVisorConfiguration_To_v1beta1_GVisorConfiguration(in *config.GVisorConfiguration, out *GVisorConfiguration, s conversion.Scope) error {
+	return autoConvert_config_GVisorConfiguration_To_v1beta1_GVisorConfiguration(in, out, s)
+}
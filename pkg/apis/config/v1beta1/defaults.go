@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+)
+
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	return RegisterDefaults(scheme)
+}
+
+// SetDefaults_GVisorConfiguration sets default values for GVisorConfiguration objects.
+func SetDefaults_GVisorConfiguration(obj *GVisorConfiguration) {
+	if ptr.Deref(obj.Debug, false) && obj.DebugLog == nil {
+		obj.DebugLog = ptr.To(config.DefaultDebugLog)
+	}
+}
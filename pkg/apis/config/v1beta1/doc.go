@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +k8s:deepcopy-gen=package
+// +k8s:conversion-gen=github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config
+// +k8s:openapi-gen=true
+// +k8s:defaulter-gen=TypeMeta
+
+//go:generate crd-ref-docs --source-path=. --config=../../../../hack/api-reference/config-config.yaml --renderer=markdown --templates-dir=$GARDENER_HACK_DIR/api-reference/template --log-level=ERROR --output-path=../../../../hack/api-reference/config-v1beta1.md
+
+// Package v1beta1 contains the GVisor container runtime configuration API resources.
+// +groupName=gvisor.runtime.extensions.config.gardener.cloud
+package v1beta1 // import "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/v1beta1"
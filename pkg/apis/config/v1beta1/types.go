@@ -0,0 +1,245 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GVisorConfiguration defines the configuration for the gVisor runtime extension.
+type GVisorConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Version is the version of gVisor installed on the nodes of the worker pool, e.g. `20260810.0`. It must be one of
+	// the versions offered by the extension. Defaults to the version the extension has been built with, i.e. the nodes
+	// are updated to newer gVisor versions with the extension. Pinning the version allows to keep a known-good release.
+	// +optional
+	Version *string `json:"version,omitempty"`
+
+	// Platform is the platform runsc uses to intercept the system calls of the sandboxed applications.
+	// Valid values are `systrap`, `ptrace`, `kvm` and `auto`. Defaults to the platform of runsc (`systrap`) if not set.
+	// `kvm` requires `/dev/kvm` to be available on the nodes, i.e. bare-metal machines or nested virtualization.
+	// With `auto`, `kvm` is used on all nodes where `/dev/kvm` is usable and `systrap` on all other nodes.
+	// +optional
+	Platform *Platform `json:"platform,omitempty"`
+	// Network is the networking mode of the sandbox. Valid values are `sandbox`, `host` and `none`.
+	// +optional
+	Network *NetworkMode `json:"network,omitempty"`
+	// Overlay is the overlay filesystem configuration of the sandbox, e.g. `root:memory`, `root:self` or `none`.
+	// It is passed as `overlay2` flag to runsc.
+	// +optional
+	Overlay *string `json:"overlay,omitempty"`
+	// FileAccess is the file access mode of the sandbox for the root filesystem. Valid values are `exclusive` and `shared`.
+	// +optional
+	FileAccess *FileAccessMode `json:"fileAccess,omitempty"`
+	// Debug enables debug logging of runsc.
+	// +optional
+	Debug *bool `json:"debug,omitempty"`
+	// DebugLog is the path of the runsc debug logs on the node. Defaults to `/var/log/runsc/%ID%/gvisor-%COMMAND%.log`
+	// if debug logging is enabled.
+	// +optional
+	DebugLog *string `json:"debugLog,omitempty"`
+	// NetRaw enables raw sockets inside the sandbox. This is required for applications like `traceroute` or `istio`
+	// init containers.
+	// +optional
+	NetRaw *bool `json:"netRaw,omitempty"`
+	// NVProxy enables the proxy for NVIDIA GPU devices. It is required to run GPU enabled containers in the sandbox.
+	// +optional
+	NVProxy *bool `json:"nvproxy,omitempty"`
+	// PanicSignal is the signal number that makes the sandbox panic and dump its stacks.
+	// It must be between 1 and 64, or -1 to disable it.
+	// +optional
+	PanicSignal *int32 `json:"panicSignal,omitempty"`
+
+	// StartupTaint makes new nodes of the worker pool start with the `gvisor.runtime.extensions.gardener.cloud/not-ready`
+	// taint with effect `NoSchedule`. The taint is removed by the installation DaemonSet once gVisor has been installed
+	// and verified on the node, so that no pods are scheduled to the node before. Only supported if gVisor is installed
+	// by the installation DaemonSet.
+	// +optional
+	StartupTaint *bool `json:"startupTaint,omitempty"`
+
+	// Rollout configures how changes of the gVisor installation, e.g. a new gVisor version or changed runsc flags, are
+	// rolled out to the nodes of the worker pool. Only supported if gVisor is installed by the installation DaemonSet.
+	// +optional
+	Rollout *Rollout `json:"rollout,omitempty"`
+
+	// Profiles are additional runsc configurations for workloads which need different sandbox trade-offs than the
+	// flags above, e.g. raw sockets or debug logging. Each profile is configured as containerd runtime handler
+	// `runsc-<name>` with its own runsc config on the nodes and is used by pods with the RuntimeClass `<name>`. The
+	// fields of a profile override the flags above, the platform is shared by all profiles. Only supported if gVisor is
+	// installed by the installation DaemonSet.
+	// +optional
+	Profiles []Profile `json:"profiles,omitempty"`
+
+	// TestImageTag is the tag for the gardener-extension-runtime-gvisor-installation image to be tested.
+	// It requires that the `gvisorInstallation.testRepository` is configured in the operator extension values and
+	// the image has been uploaded and tagged accordingly.
+	// Only used for development and testing purposes. Does not work if `gvisorInstallation.testRepository` is not specified.
+	// +optional
+	TestImageTag *string `json:"testImageTag,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GVisorStatus contains information about the gVisor installation on the nodes of a worker pool. It is written to the
+// `status.providerStatus` of the ContainerRuntime.
+type GVisorStatus struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Version is the version of the gVisor release installed on the nodes, e.g. `20260810.0`.
+	// It is not set if a test image is used.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// InstallationImage is the image containing the gVisor binaries installed on the nodes.
+	InstallationImage string `json:"installationImage"`
+	// RunscFlags are the effective flags of the `[runsc_config]` section of runsc.toml.
+	// +optional
+	RunscFlags map[string]string `json:"runscFlags,omitempty"`
+	// ConfigHash is the hash of runsc.toml on the nodes, which is also reported in the
+	// `gvisor.runtime.extensions.gardener.cloud/config-hash` annotation of the nodes.
+	// It is not set for platform `auto`, as the platform and hence runsc.toml is determined on each node.
+	// +optional
+	ConfigHash string `json:"configHash,omitempty"`
+	// Platform is the platform configured for the nodes. With `auto`, the platform is determined on each node.
+	// +optional
+	Platform Platform `json:"platform,omitempty"`
+	// Profiles are the effective runsc flags of the profiles configured on the nodes.
+	// +optional
+	Profiles []ProfileStatus `json:"profiles,omitempty"`
+	// PendingChange is a disruptive change of the installation, i.e. new binaries or a changed runsc.toml, which is
+	// deferred until the maintenance time window of the shoot. The fields above describe the installation which is
+	// rolled out to the nodes until then.
+	// +optional
+	PendingChange *PendingChange `json:"pendingChange,omitempty"`
+}
+
+// PendingChange is a change of the gVisor installation on the nodes which is deferred until the maintenance time
+// window of the shoot.
+type PendingChange struct {
+	// Version is the version of the gVisor release to be installed.
+	// It is not set if a test image is used.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// InstallationImage is the image containing the gVisor binaries to be installed.
+	InstallationImage string `json:"installationImage"`
+	// Platform is the platform to be configured.
+	// +optional
+	Platform Platform `json:"platform,omitempty"`
+	// RunscFlags are the flags of the `[runsc_config]` section of runsc.toml to be configured.
+	// +optional
+	RunscFlags map[string]string `json:"runscFlags,omitempty"`
+	// Profiles are the runsc flags of the profiles to be configured.
+	// +optional
+	Profiles []ProfileStatus `json:"profiles,omitempty"`
+}
+
+// Profile is a named runsc configuration which is used by the pods with the RuntimeClass of the same name. Unset fields
+// default to the corresponding fields of the GVisorConfiguration.
+type Profile struct {
+	// Name is the name of the profile and its RuntimeClass, e.g. `gvisor-netraw`. It must be a DNS label of at most 57
+	// characters and must not be `gvisor`, which is the RuntimeClass of the default configuration.
+	Name string `json:"name"`
+	// Network is the networking mode of the sandbox. Valid values are `sandbox`, `host` and `none`.
+	// +optional
+	Network *NetworkMode `json:"network,omitempty"`
+	// Overlay is the overlay filesystem configuration of the sandbox, e.g. `root:memory`, `root:self` or `none`.
+	// +optional
+	Overlay *string `json:"overlay,omitempty"`
+	// FileAccess is the file access mode of the sandbox for the root filesystem. Valid values are `exclusive` and `shared`.
+	// +optional
+	FileAccess *FileAccessMode `json:"fileAccess,omitempty"`
+	// Debug enables debug logging of runsc.
+	// +optional
+	Debug *bool `json:"debug,omitempty"`
+	// DebugLog is the path of the runsc debug logs on the node.
+	// +optional
+	DebugLog *string `json:"debugLog,omitempty"`
+	// NetRaw enables raw sockets inside the sandbox.
+	// +optional
+	NetRaw *bool `json:"netRaw,omitempty"`
+	// NVProxy enables the proxy for NVIDIA GPU devices.
+	// +optional
+	NVProxy *bool `json:"nvproxy,omitempty"`
+	// PanicSignal is the signal number that makes the sandbox panic and dump its stacks.
+	// It must be between 1 and 64, or -1 to disable it.
+	// +optional
+	PanicSignal *int32 `json:"panicSignal,omitempty"`
+}
+
+// ProfileStatus contains the runsc flags of a profile.
+type ProfileStatus struct {
+	// Name is the name of the profile.
+	Name string `json:"name"`
+	// RunscFlags are the flags of the `[runsc_config]` section of the runsc config of the profile.
+	// +optional
+	RunscFlags map[string]string `json:"runscFlags,omitempty"`
+}
+
+// Rollout configures how changes of the gVisor installation are rolled out to the nodes of a worker pool.
+type Rollout struct {
+	// MaxUnavailable is the maximum number of nodes of the worker pool which are updated at the same time. It can be an
+	// absolute number (e.g. `2`) or a percentage of the nodes of the worker pool (e.g. `10%`). Defaults to `1`.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// Pause is the duration the rollout waits after the installation has been verified on a node before the next node
+	// is updated. Defaults to `0s`.
+	// +optional
+	Pause *metav1.Duration `json:"pause,omitempty"`
+	// HaltOnFailure stops the rollout if the installation cannot be verified on a node, i.e. a bad release is only
+	// rolled out to the first `maxUnavailable` nodes. Otherwise, the failed node is marked as not ready and the rollout
+	// continues with the next node. Defaults to `true`.
+	// +optional
+	HaltOnFailure *bool `json:"haltOnFailure,omitempty"`
+	// Drain cordons the node and evicts its pods, respecting PodDisruptionBudgets, before containerd is restarted to
+	// apply a changed configuration. The node is uncordoned once containerd has been restarted. Defaults to `false`.
+	// +optional
+	Drain *bool `json:"drain,omitempty"`
+	// MaxConcurrentRestarts is the maximum number of nodes of the worker pool which are drained and restart containerd
+	// at the same time. Only used if `drain` is enabled. Defaults to `1`.
+	// +optional
+	MaxConcurrentRestarts *int32 `json:"maxConcurrentRestarts,omitempty"`
+}
+
+// Platform is the platform of a gVisor sandbox.
+type Platform string
+
+const (
+	// PlatformSystrap intercepts system calls with seccomp traps. It works on virtual machines without nested
+	// virtualization and is the default of runsc.
+	PlatformSystrap Platform = "systrap"
+	// PlatformPtrace intercepts system calls with ptrace. It is deprecated in runsc and only kept for compatibility.
+	PlatformPtrace Platform = "ptrace"
+	// PlatformKVM uses hardware virtualization via /dev/kvm. It is the fastest platform on bare-metal nodes.
+	PlatformKVM Platform = "kvm"
+	// PlatformAuto lets the installation on the node decide between `kvm` and `systrap` depending on whether
+	// /dev/kvm is usable on the node.
+	PlatformAuto Platform = "auto"
+)
+
+// NetworkMode is the networking mode of a gVisor sandbox.
+type NetworkMode string
+
+const (
+	// NetworkModeSandbox uses the network stack of gVisor (netstack).
+	NetworkModeSandbox NetworkMode = "sandbox"
+	// NetworkModeHost uses the network stack of the host.
+	NetworkModeHost NetworkMode = "host"
+	// NetworkModeNone disables networking except for loopback.
+	NetworkModeNone NetworkMode = "none"
+)
+
+// FileAccessMode is the file access mode of a gVisor sandbox.
+type FileAccessMode string
+
+const (
+	// FileAccessModeExclusive assumes that the sandbox has exclusive access to the files.
+	FileAccessModeExclusive FileAccessMode = "exclusive"
+	// FileAccessModeShared allows files to be changed from outside the sandbox.
+	FileAccessModeShared FileAccessMode = "shared"
+)
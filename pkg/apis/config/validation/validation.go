@@ -0,0 +1,317 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/imagevector"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+)
+
+var (
+	supportedConfigFlags = sets.New(
+		config.FlagPlatform,
+		config.FlagNetwork,
+		config.FlagOverlay,
+		config.FlagFileAccess,
+		config.FlagDebug,
+		config.FlagDebugLog,
+		config.FlagNetRaw,
+		config.FlagNVProxy,
+		config.FlagPanicSignal,
+	)
+	booleanConfigFlags = sets.New(
+		config.FlagDebug,
+		config.FlagNetRaw,
+		config.FlagNVProxy,
+	)
+
+	supportedPlatforms = sets.New(
+		string(config.PlatformSystrap),
+		string(config.PlatformPtrace),
+		string(config.PlatformKVM),
+		string(config.PlatformAuto),
+	)
+	supportedNetworkModes = sets.New(
+		string(config.NetworkModeSandbox),
+		string(config.NetworkModeHost),
+		string(config.NetworkModeNone),
+	)
+	supportedFileAccessModes = sets.New(
+		string(config.FileAccessModeExclusive),
+		string(config.FileAccessModeShared),
+	)
+	supportedOverlayMounts  = sets.New("root", "all")
+	supportedOverlayMediums = sets.New("memory", "self")
+)
+
+const (
+	overlayNone        = "none"
+	overlayMediumDir   = "dir="
+	invalidBooleanFlag = `must be either "true" or "false"`
+
+	// panicSignalDisabled is the panic signal which disables it.
+	panicSignalDisabled = -1
+	// maxSignal is the highest signal number on Linux.
+	maxSignal = 64
+
+	// maxProfileNameLength is the maximum length of profile names, so that their runtime handler `runsc-<name>` is
+	// a DNS label.
+	maxProfileNameLength = validation.DNS1123LabelMaxLength - len("runsc-")
+)
+
+// ValidateGVisorConfiguration validates the given GVisorConfiguration.
+func ValidateGVisorConfiguration(gvisorConfig *config.GVisorConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if gvisorConfig.ConfigFlags != nil {
+		allErrs = append(allErrs, validateConfigFlags(*gvisorConfig.ConfigFlags, fldPath.Child("configFlags"))...)
+	}
+
+	// The typed fields of v1alpha1 configurations are converted from the free-form config flags, hence their errors
+	// are reported for the config flag the user has set.
+	pathOf := func(fieldName, flag string) *field.Path {
+		if _, ok := ptr.Deref(gvisorConfig.ConfigFlags, nil)[flag]; ok {
+			return fldPath.Child("configFlags").Key(flag)
+		}
+		return fldPath.Child(fieldName)
+	}
+
+	if gvisorConfig.Platform != nil && !supportedPlatforms.Has(string(*gvisorConfig.Platform)) {
+		allErrs = append(allErrs, field.NotSupported(pathOf("platform", config.FlagPlatform), *gvisorConfig.Platform, sets.List(supportedPlatforms)))
+	}
+
+	allErrs = append(allErrs, validateRunscFlags(gvisorConfig.Network, gvisorConfig.Overlay, gvisorConfig.FileAccess, gvisorConfig.DebugLog, gvisorConfig.PanicSignal, pathOf)...)
+
+	if gvisorConfig.Rollout != nil {
+		allErrs = append(allErrs, validateRollout(*gvisorConfig.Rollout, fldPath.Child("rollout"))...)
+	}
+
+	// runsc refuses to start sandboxes if an overlay is used for a root filesystem with shared file access.
+	if ptr.Deref(gvisorConfig.FileAccess, "") == config.FileAccessModeShared && ptr.Deref(gvisorConfig.Overlay, overlayNone) != overlayNone {
+		allErrs = append(allErrs, field.Forbidden(pathOf("overlay", config.FlagOverlay), fmt.Sprintf("overlay cannot be used together with file access mode %q", config.FileAccessModeShared)))
+	}
+
+	allErrs = append(allErrs, validateProfiles(gvisorConfig, fldPath.Child("profiles"))...)
+
+	return allErrs
+}
+
+// ValidateGVisorVersion validates that the given gVisor version is offered by the image vector. The version is only
+// validated if it is newly set or differs from the given old version, so that worker pools which still use a version
+// which has been removed from the image vector in the meantime can still be updated.
+func ValidateGVisorVersion(version, oldVersion *string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if version == nil || (oldVersion != nil && *version == *oldVersion) {
+		return allErrs
+	}
+
+	if versions := imagevector.Versions(gvisor.RuntimeGVisorInstallationImageName); !slices.Contains(versions, *version) {
+		allErrs = append(allErrs, field.NotSupported(fldPath, *version, versions))
+	}
+
+	return allErrs
+}
+
+// ValidateInstallationMode validates that the given GVisorConfiguration only uses fields which are supported by the
+// given installation mode of the extension. The startup taint, the rollout configuration and profiles are only
+// supported if gVisor is installed by the installation DaemonSet.
+func ValidateInstallationMode(gvisorConfig *config.GVisorConfiguration, installationMode gvisor.InstallationMode, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if installationMode != gvisor.InstallationModeOperatingSystemConfig {
+		return allErrs
+	}
+
+	const msg = "not supported if gVisor is installed via the OperatingSystemConfig"
+	if ptr.Deref(gvisorConfig.StartupTaint, false) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("startupTaint"), msg))
+	}
+	if gvisorConfig.Rollout != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("rollout"), msg))
+	}
+	if len(gvisorConfig.Profiles) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("profiles"), msg))
+	}
+
+	return allErrs
+}
+
+// validateRunscFlags validates the typed runsc flags which can be configured for the worker pool and its profiles.
+// pathOf returns the path of the field with the given name, which corresponds to the given runsc flag.
+func validateRunscFlags(network *config.NetworkMode, overlay *string, fileAccess *config.FileAccessMode, debugLog *string, panicSignal *int32, pathOf func(fieldName, flag string) *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if network != nil && !supportedNetworkModes.Has(string(*network)) {
+		allErrs = append(allErrs, field.NotSupported(pathOf("network", config.FlagNetwork), *network, sets.List(supportedNetworkModes)))
+	}
+
+	if overlay != nil {
+		allErrs = append(allErrs, validateOverlay(*overlay, pathOf("overlay", config.FlagOverlay))...)
+	}
+
+	if fileAccess != nil && !supportedFileAccessModes.Has(string(*fileAccess)) {
+		allErrs = append(allErrs, field.NotSupported(pathOf("fileAccess", config.FlagFileAccess), *fileAccess, sets.List(supportedFileAccessModes)))
+	}
+
+	if debugLog != nil && !filepath.IsAbs(*debugLog) {
+		allErrs = append(allErrs, field.Invalid(pathOf("debugLog", config.FlagDebugLog), *debugLog, "must be an absolute path"))
+	}
+
+	// runsc fails to start if the panic signal is neither disabled nor a valid signal number.
+	if panicSignal != nil && *panicSignal != panicSignalDisabled && (*panicSignal < 1 || *panicSignal > maxSignal) {
+		allErrs = append(allErrs, field.Invalid(pathOf("panicSignal", config.FlagPanicSignal), *panicSignal, fmt.Sprintf("must be %d to disable it or a signal number between 1 and %d", panicSignalDisabled, maxSignal)))
+	}
+
+	return allErrs
+}
+
+func validateProfiles(gvisorConfig *config.GVisorConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.New[string]()
+	for i, profile := range gvisorConfig.Profiles {
+		var (
+			idxPath  = fldPath.Index(i)
+			namePath = idxPath.Child("name")
+		)
+
+		// The name is used for the RuntimeClass, the runtime handler `runsc-<name>` and a label key of the nodes.
+		for _, msg := range validation.IsDNS1123Label(profile.Name) {
+			allErrs = append(allErrs, field.Invalid(namePath, profile.Name, msg))
+		}
+		if len(profile.Name) > maxProfileNameLength {
+			allErrs = append(allErrs, field.TooLong(namePath, profile.Name, maxProfileNameLength))
+		}
+		if profile.Name == gvisor.RuntimeClassName {
+			allErrs = append(allErrs, field.Forbidden(namePath, fmt.Sprintf("%q is the RuntimeClass of the default configuration", gvisor.RuntimeClassName)))
+		}
+		if names.Has(profile.Name) {
+			allErrs = append(allErrs, field.Duplicate(namePath, profile.Name))
+		}
+		names.Insert(profile.Name)
+
+		allErrs = append(allErrs, validateRunscFlags(profile.Network, profile.Overlay, profile.FileAccess, profile.DebugLog, profile.PanicSignal, func(fieldName, _ string) *field.Path {
+			return idxPath.Child(fieldName)
+		})...)
+
+		// Unset fields of the profile default to the fields of the worker pool.
+		var (
+			fileAccess = cmp.Or(profile.FileAccess, gvisorConfig.FileAccess)
+			overlay    = cmp.Or(profile.Overlay, gvisorConfig.Overlay)
+		)
+		if ptr.Deref(fileAccess, "") == config.FileAccessModeShared && ptr.Deref(overlay, overlayNone) != overlayNone {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("overlay"), fmt.Sprintf("overlay cannot be used together with file access mode %q", config.FileAccessModeShared)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateRollout(rollout config.Rollout, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if rollout.MaxUnavailable != nil {
+		maxUnavailablePath := fldPath.Child("maxUnavailable")
+		switch rollout.MaxUnavailable.Type {
+		case intstr.Int:
+			if rollout.MaxUnavailable.IntVal < 1 {
+				allErrs = append(allErrs, field.Invalid(maxUnavailablePath, rollout.MaxUnavailable.IntVal, "must be greater than 0"))
+			}
+		case intstr.String:
+			percent, err := strconv.Atoi(strings.TrimSuffix(rollout.MaxUnavailable.StrVal, "%"))
+			if err != nil || !strings.HasSuffix(rollout.MaxUnavailable.StrVal, "%") {
+				allErrs = append(allErrs, field.Invalid(maxUnavailablePath, rollout.MaxUnavailable.StrVal, "must be an integer or a percentage, e.g. '10%'"))
+			} else if percent < 1 || percent > 100 {
+				allErrs = append(allErrs, field.Invalid(maxUnavailablePath, rollout.MaxUnavailable.StrVal, "must be a percentage between 1% and 100%"))
+			}
+		}
+	}
+
+	if rollout.Pause != nil && rollout.Pause.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pause"), rollout.Pause.Duration.String(), "must not be negative"))
+	}
+
+	if rollout.MaxConcurrentRestarts != nil && *rollout.MaxConcurrentRestarts < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxConcurrentRestarts"), *rollout.MaxConcurrentRestarts, "must be greater than 0"))
+	}
+
+	return allErrs
+}
+
+func validateConfigFlags(configFlags map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	keys := make([]string, 0, len(configFlags))
+	for key := range configFlags {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		var (
+			value   = configFlags[key]
+			keyPath = fldPath.Key(key)
+		)
+
+		if !supportedConfigFlags.Has(key) {
+			allErrs = append(allErrs, field.NotSupported(keyPath, key, sets.List(supportedConfigFlags)))
+			continue
+		}
+
+		if booleanConfigFlags.Has(key) && value != "true" && value != "false" {
+			allErrs = append(allErrs, field.Invalid(keyPath, value, invalidBooleanFlag))
+		}
+
+		if key == config.FlagPanicSignal {
+			if _, err := strconv.ParseInt(value, 10, 32); err != nil {
+				allErrs = append(allErrs, field.Invalid(keyPath, value, "must be an integer"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateOverlay(overlay string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if overlay == overlayNone {
+		return allErrs
+	}
+
+	mount, medium, found := strings.Cut(overlay, ":")
+	if !found {
+		return append(allErrs, field.Invalid(fldPath, overlay, `must be "none" or have the format "<mount>:<medium>"`))
+	}
+
+	if !supportedOverlayMounts.Has(mount) {
+		allErrs = append(allErrs, field.Invalid(fldPath, overlay, fmt.Sprintf("mount must be one of %q", sets.List(supportedOverlayMounts))))
+	}
+
+	if dir, ok := strings.CutPrefix(medium, overlayMediumDir); ok {
+		if !filepath.IsAbs(dir) {
+			allErrs = append(allErrs, field.Invalid(fldPath, overlay, "directory of the overlay medium must be an absolute path"))
+		}
+	} else if !supportedOverlayMediums.Has(medium) {
+		allErrs = append(allErrs, field.Invalid(fldPath, overlay, fmt.Sprintf("medium must be one of %q or %q", sets.List(supportedOverlayMediums), overlayMediumDir+"<path>")))
+	}
+
+	return allErrs
+}
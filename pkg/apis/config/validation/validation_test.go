@@ -0,0 +1,351 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gardener/gardener/pkg/utils/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	gomegatypes "github.com/onsi/gomega/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+	. "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/validation"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+)
+
+var _ = Describe("#ValidateGVisorConfiguration", func() {
+	var (
+		fldPath      = field.NewPath("providerConfig")
+		gvisorConfig *config.GVisorConfiguration
+	)
+
+	BeforeEach(func() {
+		gvisorConfig = &config.GVisorConfiguration{}
+	})
+
+	It("should allow an empty configuration", func() {
+		Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(BeEmpty())
+	})
+
+	It("should allow a valid configuration", func() {
+		gvisorConfig.Platform = new(config.PlatformAuto)
+		gvisorConfig.Network = new(config.NetworkModeHost)
+		gvisorConfig.Overlay = new("root:dir=/var/lib/gvisor")
+		gvisorConfig.FileAccess = new(config.FileAccessModeExclusive)
+		gvisorConfig.Debug = new(true)
+		gvisorConfig.DebugLog = new(config.DefaultDebugLog)
+		gvisorConfig.NetRaw = new(true)
+		gvisorConfig.NVProxy = new(false)
+		gvisorConfig.PanicSignal = new(int32(3))
+
+		Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(BeEmpty())
+	})
+
+	It("should allow valid config flags", func() {
+		gvisorConfig.ConfigFlags = &map[string]string{
+			"debug":        "true",
+			"platform":     "systrap",
+			"net-raw":      "false",
+			"nvproxy":      "true",
+			"panic-signal": "3",
+		}
+
+		Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(BeEmpty())
+	})
+
+	It("should forbid unknown config flags and invalid values", func() {
+		gvisorConfig.ConfigFlags = &map[string]string{
+			"any":          "flag",
+			"net-raw":      "yes",
+			"panic-signal": "abc",
+		}
+
+		Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeNotSupported),
+				"Field": Equal("providerConfig.configFlags[any]"),
+			})),
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":     Equal(field.ErrorTypeInvalid),
+				"Field":    Equal("providerConfig.configFlags[net-raw]"),
+				"BadValue": Equal("yes"),
+			})),
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":     Equal(field.ErrorTypeInvalid),
+				"Field":    Equal("providerConfig.configFlags[panic-signal]"),
+				"BadValue": Equal("abc"),
+			})),
+		))
+	})
+
+	It("should forbid invalid typed fields", func() {
+		gvisorConfig.Platform = new(config.Platform("xen"))
+		gvisorConfig.Network = new(config.NetworkMode("bridge"))
+		gvisorConfig.FileAccess = new(config.FileAccessMode("private"))
+		gvisorConfig.DebugLog = new("runsc.log")
+
+		Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeNotSupported),
+				"Field": Equal("providerConfig.platform"),
+			})),
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeNotSupported),
+				"Field": Equal("providerConfig.network"),
+			})),
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeNotSupported),
+				"Field": Equal("providerConfig.fileAccess"),
+			})),
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("providerConfig.debugLog"),
+			})),
+		))
+	})
+
+	DescribeTable("overlay",
+		func(overlay string, matcher gomegatypes.GomegaMatcher) {
+			gvisorConfig.Overlay = &overlay
+
+			Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(matcher)
+		},
+		Entry("none", "none", BeEmpty()),
+		Entry("root in memory", "root:memory", BeEmpty()),
+		Entry("all in self", "all:self", BeEmpty()),
+		Entry("root in directory", "root:dir=/tmp", BeEmpty()),
+		Entry("missing medium", "root", ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.overlay")})))),
+		Entry("unknown mount", "home:memory", ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.overlay")})))),
+		Entry("unknown medium", "root:disk", ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.overlay")})))),
+		Entry("relative directory", "root:dir=tmp", ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.overlay")})))),
+	)
+
+	It("should forbid an overlay together with shared file access", func() {
+		gvisorConfig.Overlay = new("root:memory")
+		gvisorConfig.FileAccess = new(config.FileAccessModeShared)
+
+		Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("providerConfig.overlay"),
+			})),
+		))
+	})
+
+	It("should allow disabling the overlay together with shared file access", func() {
+		gvisorConfig.Overlay = new("none")
+		gvisorConfig.FileAccess = new(config.FileAccessModeShared)
+
+		Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(BeEmpty())
+	})
+
+	DescribeTable("panic signal",
+		func(panicSignal int32, matcher gomegatypes.GomegaMatcher) {
+			gvisorConfig.PanicSignal = &panicSignal
+
+			Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(matcher)
+		},
+		Entry("disabled", int32(-1), BeEmpty()),
+		Entry("lowest signal", int32(1), BeEmpty()),
+		Entry("highest signal", int32(64), BeEmpty()),
+		Entry("zero", int32(0), ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.panicSignal")})))),
+		Entry("negative", int32(-5), ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.panicSignal")})))),
+		Entry("above the highest signal", int32(999), ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.panicSignal")})))),
+	)
+
+	It("should report an out of range panic signal at its config flag", func() {
+		gvisorConfig.ConfigFlags = &map[string]string{"panic-signal": "999"}
+		gvisorConfig.PanicSignal = new(int32(999))
+
+		Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":     Equal(field.ErrorTypeInvalid),
+				"Field":    Equal("providerConfig.configFlags[panic-signal]"),
+				"BadValue": Equal(int32(999)),
+			})),
+		))
+	})
+
+	DescribeTable("rollout",
+		func(rollout config.Rollout, matcher gomegatypes.GomegaMatcher) {
+			gvisorConfig.Rollout = &rollout
+
+			Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(matcher)
+		},
+		Entry("empty", config.Rollout{}, BeEmpty()),
+		Entry("absolute max unavailable", config.Rollout{MaxUnavailable: new(intstr.FromInt32(2))}, BeEmpty()),
+		Entry("percentage max unavailable", config.Rollout{MaxUnavailable: new(intstr.FromString("25%"))}, BeEmpty()),
+		Entry("pause", config.Rollout{Pause: &metav1.Duration{Duration: 5 * time.Minute}, HaltOnFailure: new(false)}, BeEmpty()),
+		Entry("zero max unavailable", config.Rollout{MaxUnavailable: new(intstr.FromInt32(0))}, ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.rollout.maxUnavailable")})))),
+		Entry("zero percent max unavailable", config.Rollout{MaxUnavailable: new(intstr.FromString("0%"))}, ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.rollout.maxUnavailable")})))),
+		Entry("more than 100 percent max unavailable", config.Rollout{MaxUnavailable: new(intstr.FromString("150%"))}, ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.rollout.maxUnavailable")})))),
+		Entry("non-percentage string max unavailable", config.Rollout{MaxUnavailable: new(intstr.FromString("2"))}, ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.rollout.maxUnavailable")})))),
+		Entry("negative pause", config.Rollout{Pause: &metav1.Duration{Duration: -time.Minute}}, ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.rollout.pause")})))),
+		Entry("drain", config.Rollout{Drain: new(true), MaxConcurrentRestarts: new(int32(2))}, BeEmpty()),
+		Entry("zero max concurrent restarts", config.Rollout{Drain: new(true), MaxConcurrentRestarts: new(int32(0))}, ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.rollout.maxConcurrentRestarts")})))),
+	)
+
+	Describe("profiles", func() {
+		It("should allow valid profiles", func() {
+			gvisorConfig.Profiles = []config.Profile{
+				{Name: "gvisor-netraw", NetRaw: new(true)},
+				{Name: "gvisor-debug", Debug: new(true), DebugLog: new("/var/log/runsc/%ID%.log")},
+				{Name: "gvisor-hostnet", Network: new(config.NetworkModeHost), Overlay: new("root:self")},
+			}
+
+			Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid invalid, reserved and duplicate names", func() {
+			gvisorConfig.Profiles = []config.Profile{
+				{Name: "Netraw"},
+				{Name: "gvisor"},
+				{Name: "gvisor-debug"},
+				{Name: "gvisor-debug"},
+				{Name: "gvisor-" + strings.Repeat("a", 51)},
+			}
+
+			Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("providerConfig.profiles[0].name"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("providerConfig.profiles[1].name"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("providerConfig.profiles[3].name"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeTooLong),
+					"Field": Equal("providerConfig.profiles[4].name"),
+				})),
+			))
+		})
+
+		It("should forbid invalid typed fields", func() {
+			gvisorConfig.Profiles = []config.Profile{{
+				Name:        "gvisor-invalid",
+				Network:     new(config.NetworkMode("bridge")),
+				Overlay:     new("root"),
+				FileAccess:  new(config.FileAccessMode("private")),
+				DebugLog:    new("runsc.log"),
+				PanicSignal: new(int32(-5)),
+			}}
+
+			Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.profiles[0].network")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.profiles[0].overlay")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.profiles[0].fileAccess")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.profiles[0].debugLog")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Field": Equal("providerConfig.profiles[0].panicSignal")})),
+			))
+		})
+
+		It("should forbid an overlay of the worker pool together with shared file access of a profile", func() {
+			gvisorConfig.Overlay = new("root:memory")
+			gvisorConfig.Profiles = []config.Profile{
+				{Name: "gvisor-shared", FileAccess: new(config.FileAccessModeShared)},
+				{Name: "gvisor-shared-without-overlay", FileAccess: new(config.FileAccessModeShared), Overlay: new("none")},
+			}
+
+			Expect(ValidateGVisorConfiguration(gvisorConfig, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("providerConfig.profiles[0].overlay"),
+				})),
+			))
+		})
+	})
+})
+
+var _ = Describe("#ValidateGVisorVersion", func() {
+	var fldPath = field.NewPath("providerConfig", "version")
+
+	BeforeEach(func() {
+		DeferCleanup(test.WithVar(&gvisor.Version, "20260810.0"))
+	})
+
+	It("should allow an unset version", func() {
+		Expect(ValidateGVisorVersion(nil, nil, fldPath)).To(BeEmpty())
+	})
+
+	It("should allow the default version", func() {
+		Expect(ValidateGVisorVersion(new("20260810.0"), nil, fldPath)).To(BeEmpty())
+	})
+
+	It("should forbid versions which are not offered", func() {
+		Expect(ValidateGVisorVersion(new("20200101.0"), nil, fldPath)).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":     Equal(field.ErrorTypeNotSupported),
+				"Field":    Equal("providerConfig.version"),
+				"BadValue": Equal("20200101.0"),
+			})),
+		))
+	})
+
+	It("should forbid changing the version to one which is not offered", func() {
+		Expect(ValidateGVisorVersion(new("20200101.0"), new("20260810.0"), fldPath)).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeNotSupported),
+				"Field": Equal("providerConfig.version"),
+			})),
+		))
+	})
+
+	It("should allow keeping a version which is not offered anymore", func() {
+		Expect(ValidateGVisorVersion(new("20200101.0"), new("20200101.0"), fldPath)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("#ValidateInstallationMode", func() {
+	var (
+		fldPath      = field.NewPath("providerConfig")
+		gvisorConfig *config.GVisorConfiguration
+	)
+
+	BeforeEach(func() {
+		gvisorConfig = &config.GVisorConfiguration{
+			StartupTaint: new(true),
+			Rollout:      &config.Rollout{Drain: new(true)},
+			Profiles:     []config.Profile{{Name: "gvisor-netraw", NetRaw: new(true)}},
+		}
+	})
+
+	It("should allow all fields in daemonset installation mode", func() {
+		Expect(ValidateInstallationMode(gvisorConfig, gvisor.InstallationModeDaemonSet, fldPath)).To(BeEmpty())
+	})
+
+	It("should forbid the fields which are only supported by the installation DaemonSet in operatingsystemconfig installation mode", func() {
+		Expect(ValidateInstallationMode(gvisorConfig, gvisor.InstallationModeOperatingSystemConfig, fldPath)).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("providerConfig.startupTaint"),
+			})),
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("providerConfig.rollout"),
+			})),
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("providerConfig.profiles"),
+			})),
+		))
+	})
+
+	It("should allow the runsc flags in operatingsystemconfig installation mode", func() {
+		gvisorConfig = &config.GVisorConfiguration{StartupTaint: new(false), NetRaw: new(true), NVProxy: new(true)}
+		Expect(ValidateInstallationMode(gvisorConfig, gvisor.InstallationModeOperatingSystemConfig, fldPath)).To(BeEmpty())
+	})
+})
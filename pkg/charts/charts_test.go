@@ -7,6 +7,7 @@ package charts_test
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
@@ -17,15 +18,19 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	releaseutil "helm.sh/helm/v4/pkg/release/v1/util"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/json"
 	runtimeutils "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
 
 	internalcharts "github.com/gardener/gardener-extension-runtime-gvisor/charts"
 	"github.com/gardener/gardener-extension-runtime-gvisor/imagevector"
 	gvisorconfiguration "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/v1alpha1"
+	gvisorconfigurationv1beta1 "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/v1beta1"
 	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/charts"
 	gvisorcmd "github.com/gardener/gardener-extension-runtime-gvisor/pkg/cmd"
 	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
@@ -66,16 +71,31 @@ var _ = Describe("Chart package test", func() {
 			mockChartRenderer = mockchartrenderer.NewMockInterface(ctrl)
 			expectedHelmValues = map[string]any{
 				"images": map[string]string{
-					"runtime-gvisor-installation": imagevector.FindImage(gvisor.RuntimeGVisorInstallationImageName),
+					"runtime-gvisor-installation": imagevector.FindImage(gvisor.RuntimeGVisorInstallationImageName, ""),
 				},
 				"config": map[string]any{
 					"nodeSelector": map[string]string{
 						extensionsv1alpha1.CRINameWorkerLabel: string(extensionsv1alpha1.CRINameContainerD),
 						"worker.gardener.cloud/pool":          "gvisor-pool",
 					},
-					"binFolder":   "/path/test",
-					"workergroup": workerGroup,
-					"configFlags": "",
+					"binFolder":            "/path/test",
+					"workergroup":          workerGroup,
+					"configFlags":          "",
+					"platform":             "systrap",
+					"containerdConfigMode": "auto",
+					"startupTaint":         false,
+					"profiles":             map[string]string{},
+					"tolerations": []corev1.Toleration{
+						{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+						{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+					},
+					"rollout": map[string]any{
+						"maxUnavailable":        1,
+						"minReadySeconds":       int64(0),
+						"haltOnFailure":         true,
+						"drain":                 false,
+						"maxConcurrentRestarts": int32(1),
+					},
 				},
 			}
 
@@ -86,7 +106,77 @@ var _ = Describe("Chart package test", func() {
 		})
 
 		It("Render Gvisor chart correctly", func() {
-			renderedValues := map[string]any{}
+			renderedValues := map[string]any{
+				"runtimeClass": map[string]any{
+					"nodeSelector": map[string]string{
+						"containerruntime.worker.gardener.cloud/gvisor":  "true",
+						"gvisor.runtime.extensions.gardener.cloud/ready": "true",
+					},
+				},
+				"profiles": []map[string]any(nil),
+			}
+
+			mockChartRenderer.EXPECT().RenderEmbeddedFS(internalcharts.InternalChart, gvisor.ChartPath, gvisor.ReleaseName, metav1.NamespaceSystem, gomock.Eq(renderedValues)).Return(&chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []releaseutil.Manifest{
+					mkManifest(charts.GVisorConfigKey),
+				},
+			}, nil)
+
+			_, err := charts.RenderGVisorChart(mockChartRenderer, gvisor.InstallationModeDaemonSet, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Render Gvisor chart without the readiness label if gVisor is installed via the OperatingSystemConfig", func() {
+			renderedValues := map[string]any{
+				"runtimeClass": map[string]any{
+					"nodeSelector": map[string]string{
+						"containerruntime.worker.gardener.cloud/gvisor": "true",
+					},
+				},
+				"profiles": []map[string]any(nil),
+			}
+
+			mockChartRenderer.EXPECT().RenderEmbeddedFS(internalcharts.InternalChart, gvisor.ChartPath, gvisor.ReleaseName, metav1.NamespaceSystem, gomock.Eq(renderedValues)).Return(&chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []releaseutil.Manifest{
+					mkManifest(charts.GVisorConfigKey),
+				},
+			}, nil)
+
+			_, err := charts.RenderGVisorChart(mockChartRenderer, gvisor.InstallationModeOperatingSystemConfig, []string{"gvisor-debug"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Render Gvisor chart with a RuntimeClass for each profile", func() {
+			renderedValues := map[string]any{
+				"runtimeClass": map[string]any{
+					"nodeSelector": map[string]string{
+						"containerruntime.worker.gardener.cloud/gvisor":  "true",
+						"gvisor.runtime.extensions.gardener.cloud/ready": "true",
+					},
+				},
+				"profiles": []map[string]any{
+					{
+						"name":    "gvisor-debug",
+						"handler": "runsc-gvisor-debug",
+						"nodeSelector": map[string]string{
+							"containerruntime.worker.gardener.cloud/gvisor":                 "true",
+							"gvisor.runtime.extensions.gardener.cloud/ready":                "true",
+							"profile.gvisor.runtime.extensions.gardener.cloud/gvisor-debug": "true",
+						},
+					},
+					{
+						"name":    "gvisor-netraw",
+						"handler": "runsc-gvisor-netraw",
+						"nodeSelector": map[string]string{
+							"containerruntime.worker.gardener.cloud/gvisor":                  "true",
+							"gvisor.runtime.extensions.gardener.cloud/ready":                 "true",
+							"profile.gvisor.runtime.extensions.gardener.cloud/gvisor-netraw": "true",
+						},
+					},
+				},
+			}
 
 			mockChartRenderer.EXPECT().RenderEmbeddedFS(internalcharts.InternalChart, gvisor.ChartPath, gvisor.ReleaseName, metav1.NamespaceSystem, gomock.Eq(renderedValues)).Return(&chartrenderer.RenderedChart{
 				ChartName: "test",
@@ -95,7 +185,7 @@ var _ = Describe("Chart package test", func() {
 				},
 			}, nil)
 
-			_, err := charts.RenderGVisorChart(mockChartRenderer)
+			_, err := charts.RenderGVisorChart(mockChartRenderer, gvisor.InstallationModeDaemonSet, []string{"gvisor-debug", "gvisor-netraw"})
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -107,7 +197,75 @@ var _ = Describe("Chart package test", func() {
 				},
 			}, nil)
 
-			_, err := charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{})
+			_, err := charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Render Gvisor uninstallation chart correctly", func() {
+			expectedHelmValues["config"] = map[string]any{
+				"nodeSelector": map[string]string{
+					extensionsv1alpha1.CRINameWorkerLabel: string(extensionsv1alpha1.CRINameContainerD),
+					"worker.gardener.cloud/pool":          "gvisor-pool",
+				},
+				"binFolder":   "/path/test",
+				"workergroup": workerGroup,
+			}
+
+			mockChartRenderer.EXPECT().RenderEmbeddedFS(internalcharts.InternalChart, gvisor.UninstallationChartPath, gvisor.UninstallationReleaseName, metav1.NamespaceSystem, gomock.Eq(expectedHelmValues)).Return(&chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []releaseutil.Manifest{
+					mkManifest(charts.GVisorConfigKey),
+				},
+			}, nil)
+
+			_, err := charts.RenderGVisorUninstallationChart(mockChartRenderer, &cr)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Render Gvisor canary chart correctly", func() {
+			expectedHelmValues["config"] = map[string]any{
+				"nodeSelector": map[string]string{
+					extensionsv1alpha1.CRINameWorkerLabel: string(extensionsv1alpha1.CRINameContainerD),
+					"worker.gardener.cloud/pool":          "gvisor-pool",
+				},
+				"workergroup":           workerGroup,
+				"schedule":              "*/5 * * * *",
+				"activeDeadlineSeconds": int64(120),
+			}
+
+			mockChartRenderer.EXPECT().RenderEmbeddedFS(internalcharts.InternalChart, gvisor.CanaryChartPath, gvisor.CanaryReleaseName, metav1.NamespaceSystem, gomock.Eq(expectedHelmValues)).Return(&chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []releaseutil.Manifest{
+					mkManifest(charts.GVisorConfigKey),
+				},
+			}, nil)
+
+			_, err := charts.RenderGVisorCanaryChart(mockChartRenderer, &cr, gvisorcmd.Config{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Render Gvisor canary chart with the image of the given installation", func() {
+			expectedHelmValues["config"] = map[string]any{
+				"nodeSelector": map[string]string{
+					extensionsv1alpha1.CRINameWorkerLabel: string(extensionsv1alpha1.CRINameContainerD),
+					"worker.gardener.cloud/pool":          "gvisor-pool",
+				},
+				"workergroup":           workerGroup,
+				"schedule":              "*/5 * * * *",
+				"activeDeadlineSeconds": int64(120),
+			}
+			expectedHelmValues["images"] = map[string]string{
+				gvisor.RuntimeGVisorInstallationImageName: "example.com/gvisor-installation:installed",
+			}
+
+			mockChartRenderer.EXPECT().RenderEmbeddedFS(internalcharts.InternalChart, gvisor.CanaryChartPath, gvisor.CanaryReleaseName, metav1.NamespaceSystem, gomock.Eq(expectedHelmValues)).Return(&chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []releaseutil.Manifest{
+					mkManifest(charts.GVisorConfigKey),
+				},
+			}, nil)
+
+			_, err := charts.RenderGVisorCanaryChart(mockChartRenderer, &cr, gvisorcmd.Config{}, &charts.Installation{Image: "example.com/gvisor-installation:installed"})
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -117,7 +275,7 @@ var _ = Describe("Chart package test", func() {
 
 				cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: rawJson}
 
-				_, err := charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{})
+				_, err := charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{}, nil)
 				var coder helper.Coder
 				Expect(errors.As(err, &coder)).To(BeTrue())
 				codes := coder.Codes()
@@ -145,6 +303,106 @@ var _ = Describe("Chart package test", func() {
 				},
 				`no kind "GVisorConfiguration" is registered for version "gvisor.runtime.extensions.config.gardener.cloud/v1alpha2" in scheme`,
 			),
+			Entry("fail on unknown flag",
+				&gvisorconfiguration.GVisorConfiguration{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gvisorconfiguration.SchemeGroupVersion.String(),
+						Kind:       "GVisorConfiguration",
+					},
+					ConfigFlags: &map[string]string{"any": "flag"},
+				},
+				`providerConfig.configFlags[any]: Unsupported value: "any"`,
+			),
+			Entry("fail on invalid boolean flag",
+				&gvisorconfiguration.GVisorConfiguration{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gvisorconfiguration.SchemeGroupVersion.String(),
+						Kind:       "GVisorConfiguration",
+					},
+					ConfigFlags: &map[string]string{"net-raw": "yes"},
+				},
+				`providerConfig.configFlags[net-raw]: Invalid value: "yes"`,
+			),
+			Entry("fail on invalid panic signal",
+				&gvisorconfiguration.GVisorConfiguration{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gvisorconfiguration.SchemeGroupVersion.String(),
+						Kind:       "GVisorConfiguration",
+					},
+					ConfigFlags: &map[string]string{"panic-signal": "abc"},
+				},
+				`providerConfig.configFlags[panic-signal]: Invalid value: "abc"`,
+			),
+			Entry("fail on out of range panic signal",
+				&gvisorconfiguration.GVisorConfiguration{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gvisorconfiguration.SchemeGroupVersion.String(),
+						Kind:       "GVisorConfiguration",
+					},
+					ConfigFlags: &map[string]string{"panic-signal": "65"},
+				},
+				`providerConfig.configFlags[panic-signal]: Invalid value: 65`,
+			),
+			Entry("fail on unsupported platform",
+				&gvisorconfiguration.GVisorConfiguration{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gvisorconfiguration.SchemeGroupVersion.String(),
+						Kind:       "GVisorConfiguration",
+					},
+					ConfigFlags: &map[string]string{"platform": "xen"},
+				},
+				`providerConfig.configFlags[platform]: Unsupported value: "xen"`,
+			),
+			Entry("fail on unsupported network flag",
+				&gvisorconfiguration.GVisorConfiguration{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gvisorconfiguration.SchemeGroupVersion.String(),
+						Kind:       "GVisorConfiguration",
+					},
+					ConfigFlags: &map[string]string{"network": "bridge"},
+				},
+				`providerConfig.configFlags[network]: Unsupported value: "bridge"`,
+			),
+			Entry("fail on invalid overlay flag",
+				&gvisorconfiguration.GVisorConfiguration{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gvisorconfiguration.SchemeGroupVersion.String(),
+						Kind:       "GVisorConfiguration",
+					},
+					ConfigFlags: &map[string]string{"overlay2": "root"},
+				},
+				`providerConfig.configFlags[overlay2]: Invalid value: "root"`,
+			),
+			Entry("fail on unsupported file access flag",
+				&gvisorconfiguration.GVisorConfiguration{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gvisorconfiguration.SchemeGroupVersion.String(),
+						Kind:       "GVisorConfiguration",
+					},
+					ConfigFlags: &map[string]string{"file-access": "private"},
+				},
+				`providerConfig.configFlags[file-access]: Unsupported value: "private"`,
+			),
+			Entry("fail on relative debug log flag",
+				&gvisorconfiguration.GVisorConfiguration{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gvisorconfiguration.SchemeGroupVersion.String(),
+						Kind:       "GVisorConfiguration",
+					},
+					ConfigFlags: &map[string]string{"debug": "true", "debug-log": "runsc.log"},
+				},
+				`providerConfig.configFlags[debug-log]: Invalid value: "runsc.log"`,
+			),
+			Entry("fail on overlay flag with shared file access flag",
+				&gvisorconfiguration.GVisorConfiguration{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gvisorconfiguration.SchemeGroupVersion.String(),
+						Kind:       "GVisorConfiguration",
+					},
+					ConfigFlags: &map[string]string{"file-access": "shared", "overlay2": "root:memory"},
+				},
+				`providerConfig.configFlags[overlay2]: Forbidden: overlay cannot be used together with file access mode "shared"`,
+			),
 		)
 
 		DescribeTable("Render Gvisor installation chart correctly with valid provider config",
@@ -176,7 +434,7 @@ var _ = Describe("Chart package test", func() {
 					},
 				}, nil)
 
-				_, err = charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{})
+				_, err = charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{}, nil)
 				Expect(err).NotTo(HaveOccurred())
 			},
 			Entry("no-flags", map[string]string{}, ""),
@@ -186,8 +444,231 @@ var _ = Describe("Chart package test", func() {
 				map[string]string{"debug": "true"},
 				"debug = \"true\"\ndebug-log = \"/var/log/runsc/%ID%/gvisor-%COMMAND%.log\"\n"),
 			Entry("panic-signal",
-				map[string]string{"panic-signal": "123"},
-				"panic-signal = \"123\"\n"),
+				map[string]string{"panic-signal": "12"},
+				"panic-signal = \"12\"\n"),
+			Entry("typed flags",
+				map[string]string{"network": "host", "overlay2": "none", "file-access": "shared"},
+				"network = \"host\"\noverlay2 = \"none\"\nfile-access = \"shared\"\n"),
+		)
+
+		It("Render Gvisor installation chart with startup taint", func() {
+			rawJson, err := json.Marshal(&gvisorconfigurationv1beta1.GVisorConfiguration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: gvisorconfigurationv1beta1.SchemeGroupVersion.String(),
+					Kind:       "GVisorConfiguration",
+				},
+				StartupTaint: ptr.To(true),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: rawJson}
+
+			expectedHelmValues["config"].(map[string]any)["startupTaint"] = true
+			expectedHelmValues["config"].(map[string]any)["tolerations"] = []corev1.Toleration{
+				{Key: "gvisor.runtime.extensions.gardener.cloud/not-ready", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+			}
+
+			mockChartRenderer.EXPECT().RenderEmbeddedFS(internalcharts.InternalChart, gvisor.InstallationChartPath, gvisor.InstallationReleaseName, metav1.NamespaceSystem, gomock.Eq(expectedHelmValues)).Return(&chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []releaseutil.Manifest{
+					mkManifest(charts.GVisorConfigKey),
+				},
+			}, nil)
+
+			_, err = charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Render Gvisor installation chart with rollout configuration", func() {
+			rawJson, err := json.Marshal(&gvisorconfigurationv1beta1.GVisorConfiguration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: gvisorconfigurationv1beta1.SchemeGroupVersion.String(),
+					Kind:       "GVisorConfiguration",
+				},
+				Rollout: &gvisorconfigurationv1beta1.Rollout{
+					MaxUnavailable:        ptr.To(intstr.FromString("20%")),
+					Pause:                 &metav1.Duration{Duration: 2 * time.Minute},
+					HaltOnFailure:         ptr.To(false),
+					Drain:                 ptr.To(true),
+					MaxConcurrentRestarts: ptr.To[int32](2),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: rawJson}
+
+			expectedHelmValues["config"].(map[string]any)["rollout"] = map[string]any{
+				"maxUnavailable":        "20%",
+				"minReadySeconds":       int64(120),
+				"haltOnFailure":         false,
+				"drain":                 true,
+				"maxConcurrentRestarts": int32(2),
+			}
+
+			mockChartRenderer.EXPECT().RenderEmbeddedFS(internalcharts.InternalChart, gvisor.InstallationChartPath, gvisor.InstallationReleaseName, metav1.NamespaceSystem, gomock.Eq(expectedHelmValues)).Return(&chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []releaseutil.Manifest{
+					mkManifest(charts.GVisorConfigKey),
+				},
+			}, nil)
+
+			_, err = charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Render Gvisor installation chart with profiles", func() {
+			rawJson, err := json.Marshal(&gvisorconfigurationv1beta1.GVisorConfiguration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: gvisorconfigurationv1beta1.SchemeGroupVersion.String(),
+					Kind:       "GVisorConfiguration",
+				},
+				Network: new(gvisorconfigurationv1beta1.NetworkModeHost),
+				Profiles: []gvisorconfigurationv1beta1.Profile{
+					{Name: "gvisor-netraw", NetRaw: new(true)},
+					{Name: "gvisor-sandbox", Network: new(gvisorconfigurationv1beta1.NetworkModeSandbox), Debug: new(true)},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: rawJson}
+
+			// Profiles override the flags of the provider config.
+			expectedHelmValues["config"].(map[string]any)["configFlags"] = "network = \"host\"\n"
+			expectedHelmValues["config"].(map[string]any)["profiles"] = map[string]string{
+				"gvisor-netraw":  "network = \"host\"\nnet-raw = \"true\"\n",
+				"gvisor-sandbox": "network = \"sandbox\"\ndebug = \"true\"\ndebug-log = \"/var/log/runsc/%ID%/gvisor-%COMMAND%.log\"\n",
+			}
+
+			mockChartRenderer.EXPECT().RenderEmbeddedFS(internalcharts.InternalChart, gvisor.InstallationChartPath, gvisor.InstallationReleaseName, metav1.NamespaceSystem, gomock.Eq(expectedHelmValues)).Return(&chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []releaseutil.Manifest{
+					mkManifest(charts.GVisorConfigKey),
+				},
+			}, nil)
+
+			_, err = charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Render Gvisor installation chart with a deferred installation", func() {
+			rawJson, err := json.Marshal(&gvisorconfigurationv1beta1.GVisorConfiguration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: gvisorconfigurationv1beta1.SchemeGroupVersion.String(),
+					Kind:       "GVisorConfiguration",
+				},
+				Network:      new(gvisorconfigurationv1beta1.NetworkModeHost),
+				StartupTaint: ptr.To(true),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: rawJson}
+
+			// The installation on the nodes is kept while the startup taint is configured right away.
+			expectedHelmValues["images"] = map[string]string{"runtime-gvisor-installation": "example.com/gvisor-installation:v1"}
+			expectedHelmValues["config"].(map[string]any)["configFlags"] = "platform = \"kvm\"\nnet-raw = \"true\"\npanic-signal = \"6\"\n"
+			expectedHelmValues["config"].(map[string]any)["platform"] = "kvm"
+			expectedHelmValues["config"].(map[string]any)["startupTaint"] = true
+			expectedHelmValues["config"].(map[string]any)["tolerations"] = []corev1.Toleration{
+				{Key: "gvisor.runtime.extensions.gardener.cloud/not-ready", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+			}
+
+			mockChartRenderer.EXPECT().RenderEmbeddedFS(internalcharts.InternalChart, gvisor.InstallationChartPath, gvisor.InstallationReleaseName, metav1.NamespaceSystem, gomock.Eq(expectedHelmValues)).Return(&chartrenderer.RenderedChart{
+				ChartName: "test",
+				Manifests: []releaseutil.Manifest{
+					mkManifest(charts.GVisorConfigKey),
+				},
+			}, nil)
+
+			_, err = charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{}, &charts.Installation{
+				Version:          "20260101.0",
+				Image:            "example.com/gvisor-installation:v1",
+				Platform:         "kvm",
+				RunscConfigFlags: map[string]string{"panic-signal": "6", "net-raw": "true", "platform": "kvm"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		DescribeTable("Render Gvisor installation chart with platform",
+			func(platform *gvisorconfigurationv1beta1.Platform, expectedConfigFlags, expectedPlatform string) {
+				rawJson, err := json.Marshal(&gvisorconfigurationv1beta1.GVisorConfiguration{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: gvisorconfigurationv1beta1.SchemeGroupVersion.String(),
+						Kind:       "GVisorConfiguration",
+					},
+					Platform: platform,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: rawJson}
+
+				expectedHelmValues["config"].(map[string]any)["configFlags"] = expectedConfigFlags
+				expectedHelmValues["config"].(map[string]any)["platform"] = expectedPlatform
+
+				mockChartRenderer.EXPECT().RenderEmbeddedFS(internalcharts.InternalChart, gvisor.InstallationChartPath, gvisor.InstallationReleaseName, metav1.NamespaceSystem, gomock.Eq(expectedHelmValues)).Return(&chartrenderer.RenderedChart{
+					ChartName: "test",
+					Manifests: []releaseutil.Manifest{
+						mkManifest(charts.GVisorConfigKey),
+					},
+				}, nil)
+
+				_, err = charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+			},
+			Entry("default", nil, "", "systrap"),
+			Entry("ptrace", new(gvisorconfigurationv1beta1.PlatformPtrace), "platform = \"ptrace\"\n", "ptrace"),
+			Entry("kvm", new(gvisorconfigurationv1beta1.PlatformKVM), "platform = \"kvm\"\n", "kvm"),
+			Entry("auto is determined on the node", new(gvisorconfigurationv1beta1.PlatformAuto), "", "auto"),
+		)
+
+		DescribeTable("Render Gvisor installation chart correctly with typed provider config",
+			func(providerConfig *gvisorconfigurationv1beta1.GVisorConfiguration, expectedConfigFlags string) {
+				providerConfig.TypeMeta = metav1.TypeMeta{
+					APIVersion: gvisorconfigurationv1beta1.SchemeGroupVersion.String(),
+					Kind:       "GVisorConfiguration",
+				}
+
+				rawJson, err := json.Marshal(providerConfig)
+				Expect(err).NotTo(HaveOccurred())
+
+				cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: rawJson}
+
+				expectedHelmValues["config"].(map[string]any)["configFlags"] = expectedConfigFlags
+
+				mockChartRenderer.EXPECT().RenderEmbeddedFS(internalcharts.InternalChart, gvisor.InstallationChartPath, gvisor.InstallationReleaseName, metav1.NamespaceSystem, gomock.Eq(expectedHelmValues)).Return(&chartrenderer.RenderedChart{
+					ChartName: "test",
+					Manifests: []releaseutil.Manifest{
+						mkManifest(charts.GVisorConfigKey),
+					},
+				}, nil)
+
+				_, err = charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+			},
+			Entry("no fields", &gvisorconfigurationv1beta1.GVisorConfiguration{}, ""),
+			Entry("disabled fields",
+				&gvisorconfigurationv1beta1.GVisorConfiguration{Debug: new(false), NVProxy: new(false), NetRaw: new(false)},
+				"net-raw = \"false\"\nnvproxy = \"false\"\ndebug = \"false\"\n"),
+			Entry("debug with default log path",
+				&gvisorconfigurationv1beta1.GVisorConfiguration{Debug: new(true)},
+				"debug = \"true\"\ndebug-log = \"/var/log/runsc/%ID%/gvisor-%COMMAND%.log\"\n"),
+			Entry("debug with custom log path",
+				&gvisorconfigurationv1beta1.GVisorConfiguration{Debug: new(true), DebugLog: new("/var/log/runsc/%ID%.log")},
+				"debug = \"true\"\ndebug-log = \"/var/log/runsc/%ID%.log\"\n"),
+			Entry("all fields",
+				&gvisorconfigurationv1beta1.GVisorConfiguration{
+					Platform:    new(gvisorconfigurationv1beta1.PlatformSystrap),
+					Network:     new(gvisorconfigurationv1beta1.NetworkModeSandbox),
+					Overlay:     new("root:memory"),
+					FileAccess:  new(gvisorconfigurationv1beta1.FileAccessModeExclusive),
+					NetRaw:      new(true),
+					NVProxy:     new(true),
+					PanicSignal: new(int32(3)),
+				},
+				"platform = \"systrap\"\nnetwork = \"sandbox\"\noverlay2 = \"root:memory\"\nfile-access = \"exclusive\"\nnet-raw = \"true\"\nnvproxy = \"true\"\npanic-signal = \"3\"\n"),
 		)
 
 		DescribeTable("Render Gvisor installation chart with test image",
@@ -221,7 +702,7 @@ var _ = Describe("Chart package test", func() {
 					},
 				}, nil)
 
-				_, err = charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{InstallationTestRepository: testRepository})
+				_, err = charts.RenderGVisorInstallationChart(mockChartRenderer, &cr, gvisorcmd.Config{InstallationTestRepository: testRepository}, nil)
 				Expect(err).NotTo(HaveOccurred())
 			},
 			Entry("default", nil, nil, defaultImageName),
@@ -5,95 +5,211 @@
 package charts
 
 import (
+	"cmp"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/gardener/gardener/pkg/chartrenderer"
+	gardenerutils "github.com/gardener/gardener/pkg/utils/gardener"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
-	runtimeutils "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/clock"
 	"k8s.io/utils/ptr"
 
 	"github.com/gardener/gardener-extension-runtime-gvisor/charts"
 	"github.com/gardener/gardener-extension-runtime-gvisor/imagevector"
-	gvisorconfiguration "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/v1alpha1"
+	gvisorconfiguration "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/install"
+	gvisorconfigurationv1beta1 "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/v1beta1"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/validation"
 	gvisorcmd "github.com/gardener/gardener-extension-runtime-gvisor/pkg/cmd"
 	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/nodeinstaller"
 )
 
-// GVisorConfigKey is the key for the gVisor configuration.
-const GVisorConfigKey = "config.yaml"
+const (
+	// GVisorConfigKey is the key for the gVisor configuration.
+	GVisorConfigKey = "config.yaml"
+
+	// CanarySchedule is the schedule of the canary probe starting a gVisor sandbox on the nodes of a worker pool.
+	CanarySchedule = "*/5 * * * *"
+	// CanaryActiveDeadline is the duration after which a canary probe which did not complete is failed, e.g. because
+	// the sandbox could not be started.
+	CanaryActiveDeadline = 2 * time.Minute
+)
 
 var decoder runtime.Decoder
 
 func init() {
 	scheme := runtime.NewScheme()
-	runtimeutils.Must(gvisorconfiguration.AddToScheme(scheme))
+	install.Install(scheme)
 	decoder = serializer.NewCodecFactory(scheme).UniversalDecoder()
 }
 
-// RenderGVisorInstallationChart renders the gVisor installation chart
-func RenderGVisorInstallationChart(renderer chartrenderer.Interface, cr *extensionsv1alpha1.ContainerRuntime, serviceConfig gvisorcmd.Config) ([]byte, error) {
-	providerConfig := &gvisorconfiguration.GVisorConfiguration{}
-	if cr.Spec.ProviderConfig != nil {
-		if _, _, err := decoder.Decode(cr.Spec.ProviderConfig.Raw, nil, providerConfig); err != nil {
-			// TODO: Add admission component and move validation there by using strict decoding, for example: https://github.com/gardener/gardener-extension-provider-aws/pull/307.
-			return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("could not decode provider config: %w", err), gardencorev1beta1.ErrorConfigurationProblem)
-		}
+// Installation is the part of the configuration of the gVisor installation whose change is disruptive for the nodes,
+// i.e. it replaces the binaries or changes runsc.toml, which requires a restart of containerd.
+type Installation struct {
+	// Version is the version of the gVisor release contained in Image. It is empty if the test image is used.
+	Version string
+	// Image is the gardener-extension-runtime-gvisor-installation image containing the binaries.
+	Image string
+	// Platform is the configured platform.
+	Platform gvisorconfiguration.Platform
+	// RunscConfigFlags are the typed runsc flags of the `[runsc_config]` section of runsc.toml.
+	RunscConfigFlags map[string]string
+	// Profiles are the typed runsc flags of the runsc configs of the profiles by their name.
+	Profiles map[string]map[string]string
+}
+
+// DesiredInstallation returns the Installation for the given configuration.
+func DesiredInstallation(providerConfig *gvisorconfiguration.GVisorConfiguration, serviceConfig gvisorcmd.Config) Installation {
+	return Installation{
+		Version:          GVisorVersion(providerConfig, serviceConfig),
+		Image:            InstallationImage(providerConfig, serviceConfig),
+		Platform:         ptr.Deref(providerConfig.Platform, gvisorconfiguration.PlatformSystrap),
+		RunscConfigFlags: RunscConfigFlags(providerConfig),
+		Profiles:         ProfilesRunscConfigFlags(providerConfig),
 	}
+}
 
-	runscConfigFlags := ""
-	if providerConfig.ConfigFlags != nil && len(*providerConfig.ConfigFlags) > 0 {
-		for key, value := range *providerConfig.ConfigFlags {
-			// the API allows to set arbitrary flags, but we only allow the following flags for now
-			// A list of all supported flags can be found here: https://github.com/google/gvisor/blob/master/runsc/config/flags.go
-			// and https://github.com/google/gvisor/blob/master/runsc/config/config.go#L46
-			if key == "net-raw" && (value == "true" || value == "false") {
-				runscConfigFlags += fmt.Sprintf("%s = \"%s\"\n", key, value)
-			}
-			if key == "debug" && value == "true" {
-				runscConfigFlags += fmt.Sprintf("%s = \"%s\"\n", key, "true")
-				runscConfigFlags += "debug-log = \"/var/log/runsc/%ID%/gvisor-%COMMAND%.log\"\n"
-			}
-			if key == "nvproxy" && value == "true" {
-				runscConfigFlags += fmt.Sprintf("%s = \"%s\"\n", key, "true")
-			}
-			if key == "panic-signal" {
-				if _, err := strconv.Atoi(value); err == nil {
-					runscConfigFlags += fmt.Sprintf("%s = %q\n", key, value)
-				}
-			}
+// Equal returns true if both installations result in the same binaries, runsc.toml and runsc configs of the profiles
+// on the nodes.
+func (i Installation) Equal(other Installation) bool {
+	return i.Image == other.Image && i.Platform == other.Platform && maps.Equal(i.RunscConfigFlags, other.RunscConfigFlags) &&
+		maps.EqualFunc(i.Profiles, other.Profiles, maps.Equal)
+}
+
+// RenderRunscConfigFlags renders the runsc flags of the installation into the `[runsc_config]` section of runsc.toml.
+func (i Installation) RenderRunscConfigFlags() string {
+	return renderRunscConfigFlags(i.RunscConfigFlags)
+}
+
+// RenderProfiles renders the runsc flags of the profiles of the installation into the `[runsc_config]` sections of
+// their runsc configs.
+func (i Installation) RenderProfiles() map[string]string {
+	profiles := make(map[string]string, len(i.Profiles))
+	for name, flags := range i.Profiles {
+		profiles[name] = renderRunscConfigFlags(flags)
+	}
+	return profiles
+}
+
+// InstallationToRollOut returns the Installation to roll out to the nodes of a worker pool, given the Installation
+// which is currently rolled out according to the provider status of its ContainerRuntime, and the desired Installation
+// if it is deferred. Disruptive changes, i.e. new binaries or a changed runsc.toml which requires a restart of
+// containerd, are only rolled out in the maintenance time window of the shoot, independent of how gVisor is installed.
+func InstallationToRollOut(current *Installation, desired Installation, providerConfig *gvisorconfiguration.GVisorConfiguration, cluster *extensionscontroller.Cluster, clock clock.Clock) (Installation, *Installation) {
+	// A configured version which is not offered by the image vector anymore is kept as long as it is installed on the
+	// nodes, instead of switching the worker pool to the default version.
+	if current != nil && !VersionOffered(providerConfig) && current.Version == *providerConfig.Version {
+		desired.Version, desired.Image = current.Version, current.Image
+	}
+
+	// New worker pools are installed right away, and the nodes of hibernated shoots are not disrupted.
+	if current == nil || current.Equal(desired) || extensionscontroller.IsHibernated(cluster) || gardenerutils.IsNowInEffectiveShootMaintenanceTimeWindow(cluster.Shoot, clock) {
+		return desired, nil
+	}
+	return *current, &desired
+}
+
+// CurrentInstallation returns the Installation which has been rolled out to the nodes of the worker pool of the given
+// ContainerRuntime according to its provider status, or nil if it is unknown.
+func CurrentInstallation(cr *extensionsv1alpha1.ContainerRuntime) (*Installation, error) {
+	if cr.Status.ProviderStatus == nil || cr.Status.ProviderStatus.Raw == nil {
+		return nil, nil
+	}
+
+	status := &gvisorconfigurationv1beta1.GVisorStatus{}
+	if err := json.Unmarshal(cr.Status.ProviderStatus.Raw, status); err != nil {
+		return nil, fmt.Errorf("could not decode provider status: %w", err)
+	}
+	if status.InstallationImage == "" {
+		return nil, nil
+	}
+
+	installation := &Installation{
+		Version:          status.Version,
+		Image:            status.InstallationImage,
+		Platform:         gvisorconfiguration.Platform(status.Platform),
+		RunscConfigFlags: status.RunscFlags,
+		Profiles:         profilesFromStatus(status.Profiles),
+	}
+	// The platform is not reported by previous versions of the extension. It is part of the runsc flags unless the
+	// default platform or `auto` is used, for which no config hash is reported.
+	if installation.Platform == "" {
+		switch {
+		case status.RunscFlags[gvisorconfiguration.FlagPlatform] != "":
+			installation.Platform = gvisorconfiguration.Platform(status.RunscFlags[gvisorconfiguration.FlagPlatform])
+		case status.ConfigHash == "":
+			installation.Platform = gvisorconfiguration.PlatformAuto
+		default:
+			installation.Platform = gvisorconfiguration.PlatformSystrap
 		}
 	}
+	return installation, nil
+}
 
-	nodeSelectorValue := map[string]string{
-		extensionsv1alpha1.CRINameWorkerLabel: string(extensionsv1alpha1.CRINameContainerD),
+// profilesFromStatus returns the runsc flags of the given profile statuses by profile name.
+func profilesFromStatus(statuses []gvisorconfigurationv1beta1.ProfileStatus) map[string]map[string]string {
+	if len(statuses) == 0 {
+		return nil
 	}
 
-	for key, value := range cr.Spec.WorkerPool.Selector.MatchLabels {
-		nodeSelectorValue[key] = value
+	profiles := make(map[string]map[string]string, len(statuses))
+	for _, status := range statuses {
+		profiles[status.Name] = status.RunscFlags
 	}
+	return profiles
+}
 
-	configChartValues := map[string]any{
-		"binFolder":    cr.Spec.BinaryPath,
-		"nodeSelector": nodeSelectorValue,
-		"workergroup":  cr.Spec.WorkerPool.Name,
-		"configFlags":  runscConfigFlags,
+// RenderGVisorInstallationChart renders the gVisor installation chart. The given installation overrides the
+// disruptive part of the configuration, e.g. while a change is deferred until the maintenance time window of the
+// shoot. The configuration of the ContainerRuntime is installed if it is nil.
+func RenderGVisorInstallationChart(renderer chartrenderer.Interface, cr *extensionsv1alpha1.ContainerRuntime, serviceConfig gvisorcmd.Config, installation *Installation) ([]byte, error) {
+	providerConfig, err := DecodeProviderConfig(cr.Spec.ProviderConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if installation == nil {
+		installation = ptr.To(DesiredInstallation(providerConfig, serviceConfig))
+	}
+
+	containerdConfigMode := gvisor.ContainerdConfigModeAuto
+	if serviceConfig.ContainerdConfigMode != "" {
+		containerdConfigMode = serviceConfig.ContainerdConfigMode
 	}
 
-	imageName := imagevector.FindImage(gvisor.RuntimeGVisorInstallationImageName)
-	if ptr.Deref(serviceConfig.InstallationTestRepository, "") != "" &&
-		ptr.Deref(providerConfig.TestImageTag, "") != "" {
-		imageName = *serviceConfig.InstallationTestRepository + ":" + *providerConfig.TestImageTag
+	configChartValues := map[string]any{
+		"binFolder":            cr.Spec.BinaryPath,
+		"nodeSelector":         nodeSelector(cr),
+		"workergroup":          cr.Spec.WorkerPool.Name,
+		"configFlags":          installation.RenderRunscConfigFlags(),
+		"profiles":             installation.RenderProfiles(),
+		"platform":             string(installation.Platform),
+		"containerdConfigMode": string(containerdConfigMode),
+		"startupTaint":         ptr.Deref(providerConfig.StartupTaint, false),
+		"tolerations":          tolerations(providerConfig),
+		"rollout":              rollout(providerConfig),
 	}
+
 	gvisorChartValues := map[string]any{
 		"config": configChartValues,
 		"images": map[string]string{
-			gvisor.RuntimeGVisorInstallationImageName: imageName,
+			gvisor.RuntimeGVisorInstallationImageName: installation.Image,
 		},
 	}
 
@@ -104,9 +220,307 @@ func RenderGVisorInstallationChart(renderer chartrenderer.Interface, cr *extensi
 	return release.Manifest(), nil
 }
 
-// RenderGVisorChart renders the gVisor chart
-func RenderGVisorChart(renderer chartrenderer.Interface) ([]byte, error) {
-	gvisorChartValues := map[string]any{}
+// DecodeProviderConfig decodes and validates the given provider config of the gVisor container runtime. Errors are
+// returned as configuration problems.
+func DecodeProviderConfig(rawProviderConfig *runtime.RawExtension) (*gvisorconfiguration.GVisorConfiguration, error) {
+	providerConfig := &gvisorconfiguration.GVisorConfiguration{}
+	if rawProviderConfig != nil {
+		if _, _, err := decoder.Decode(rawProviderConfig.Raw, nil, providerConfig); err != nil {
+			// The provider config is decoded strictly and validated by the admission component already, see pkg/admission.
+			return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("could not decode provider config: %w", err), gardencorev1beta1.ErrorConfigurationProblem)
+		}
+	}
+
+	if errList := validation.ValidateGVisorConfiguration(providerConfig, field.NewPath("providerConfig")); len(errList) > 0 {
+		return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("invalid provider config: %w", errList.ToAggregate()), gardencorev1beta1.ErrorConfigurationProblem)
+	}
+
+	return providerConfig, nil
+}
+
+// ValidateInstallationMode returns a configuration problem if the given provider config uses fields which are not
+// supported by the given installation mode, instead of ignoring them silently.
+func ValidateInstallationMode(providerConfig *gvisorconfiguration.GVisorConfiguration, installationMode gvisor.InstallationMode) error {
+	if errList := validation.ValidateInstallationMode(providerConfig, installationMode, field.NewPath("providerConfig")); len(errList) > 0 {
+		return v1beta1helper.NewErrorWithCodes(fmt.Errorf("invalid provider config: %w", errList.ToAggregate()), gardencorev1beta1.ErrorConfigurationProblem)
+	}
+	return nil
+}
+
+// InstallationImage returns the gardener-extension-runtime-gvisor-installation image for the given configuration.
+// The test image is used if a test repository is configured and the provider config contains a test image tag.
+// Otherwise, the image containing the configured gVisor version is used, or the default image if no version is
+// configured.
+func InstallationImage(providerConfig *gvisorconfiguration.GVisorConfiguration, serviceConfig gvisorcmd.Config) string {
+	if usesTestImage(providerConfig, serviceConfig) {
+		return *serviceConfig.InstallationTestRepository + ":" + *providerConfig.TestImageTag
+	}
+	return imagevector.FindImage(gvisor.RuntimeGVisorInstallationImageName, offeredVersion(providerConfig))
+}
+
+// GVisorVersion returns the version of the gVisor release contained in the installation image for the given
+// configuration. It is empty if the test image is used, as its version is unknown.
+func GVisorVersion(providerConfig *gvisorconfiguration.GVisorConfiguration, serviceConfig gvisorcmd.Config) string {
+	if usesTestImage(providerConfig, serviceConfig) {
+		return ""
+	}
+	return cmp.Or(offeredVersion(providerConfig), imagevector.DefaultVersion(gvisor.RuntimeGVisorInstallationImageName))
+}
+
+// VersionOffered returns false if the gVisor version configured in the given provider config is not offered by the
+// image vector anymore, e.g. because it has been removed in a later release of the extension. Such configurations are
+// still accepted for existing worker pools, see validation.ValidateGVisorVersion, and the default version is used
+// instead unless the controller keeps the installed release.
+func VersionOffered(providerConfig *gvisorconfiguration.GVisorConfiguration) bool {
+	return providerConfig.Version == nil || slices.Contains(imagevector.Versions(gvisor.RuntimeGVisorInstallationImageName), *providerConfig.Version)
+}
+
+// offeredVersion returns the configured gVisor version if it is offered by the image vector, and the empty string,
+// i.e. the default version, otherwise.
+func offeredVersion(providerConfig *gvisorconfiguration.GVisorConfiguration) string {
+	if !VersionOffered(providerConfig) {
+		return ""
+	}
+	return ptr.Deref(providerConfig.Version, "")
+}
+
+func usesTestImage(providerConfig *gvisorconfiguration.GVisorConfiguration, serviceConfig gvisorcmd.Config) bool {
+	return ptr.Deref(serviceConfig.InstallationTestRepository, "") != "" && ptr.Deref(providerConfig.TestImageTag, "") != ""
+}
+
+// RenderGVisorUninstallationChart renders the gVisor uninstallation chart which removes gVisor from the nodes of the
+// worker pool of the given ContainerRuntime.
+func RenderGVisorUninstallationChart(renderer chartrenderer.Interface, cr *extensionsv1alpha1.ContainerRuntime) ([]byte, error) {
+	gvisorChartValues := map[string]any{
+		"config": map[string]any{
+			"binFolder":    cr.Spec.BinaryPath,
+			"nodeSelector": nodeSelector(cr),
+			"workergroup":  cr.Spec.WorkerPool.Name,
+		},
+		"images": map[string]string{
+			gvisor.RuntimeGVisorInstallationImageName: imagevector.FindImage(gvisor.RuntimeGVisorInstallationImageName, ""),
+		},
+	}
+
+	release, err := renderer.RenderEmbeddedFS(charts.InternalChart, gvisor.UninstallationChartPath, gvisor.UninstallationReleaseName, metav1.NamespaceSystem, gvisorChartValues)
+	if err != nil {
+		return nil, err
+	}
+	return release.Manifest(), nil
+}
+
+// RenderGVisorCanaryChart renders the gVisor canary chart which periodically starts a gVisor sandbox on the nodes of
+// the worker pool of the given ContainerRuntime. The canary uses the image of the given installation, which has to be
+// the one rolled out to the nodes, e.g. while a change is deferred until the maintenance time window of the shoot.
+// The configuration of the ContainerRuntime is used if it is nil.
+func RenderGVisorCanaryChart(renderer chartrenderer.Interface, cr *extensionsv1alpha1.ContainerRuntime, serviceConfig gvisorcmd.Config, installation *Installation) ([]byte, error) {
+	if installation == nil {
+		providerConfig, err := DecodeProviderConfig(cr.Spec.ProviderConfig)
+		if err != nil {
+			return nil, err
+		}
+		installation = ptr.To(DesiredInstallation(providerConfig, serviceConfig))
+	}
+
+	gvisorChartValues := map[string]any{
+		"config": map[string]any{
+			"nodeSelector":          nodeSelector(cr),
+			"workergroup":           cr.Spec.WorkerPool.Name,
+			"schedule":              CanarySchedule,
+			"activeDeadlineSeconds": int64(CanaryActiveDeadline.Seconds()),
+		},
+		"images": map[string]string{
+			gvisor.RuntimeGVisorInstallationImageName: installation.Image,
+		},
+	}
+
+	release, err := renderer.RenderEmbeddedFS(charts.InternalChart, gvisor.CanaryChartPath, gvisor.CanaryReleaseName, metav1.NamespaceSystem, gvisorChartValues)
+	if err != nil {
+		return nil, err
+	}
+	return release.Manifest(), nil
+}
+
+// tolerations returns the tolerations of the installation DaemonSet, which has to be scheduled to all nodes of the
+// worker pool. The startup taint is tolerated explicitly as it is removed by the installer.
+func tolerations(providerConfig *gvisorconfiguration.GVisorConfiguration) []corev1.Toleration {
+	var tolerations []corev1.Toleration
+	if ptr.Deref(providerConfig.StartupTaint, false) {
+		tolerations = append(tolerations, corev1.Toleration{Key: gvisor.TaintKeyNotReady, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule})
+	}
+
+	return append(tolerations,
+		corev1.Toleration{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		corev1.Toleration{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	)
+}
+
+// rollout returns the rollout configuration of the installation DaemonSet. By default, the nodes are updated one after
+// another and the rollout halts on the first node on which the installation cannot be verified.
+func rollout(providerConfig *gvisorconfiguration.GVisorConfiguration) map[string]any {
+	var (
+		maxUnavailable        any = 1
+		pause                 time.Duration
+		haltOnFailure         = true
+		drain                 bool
+		maxConcurrentRestarts int32 = 1
+	)
+
+	if r := providerConfig.Rollout; r != nil {
+		if r.MaxUnavailable != nil {
+			if r.MaxUnavailable.Type == intstr.String {
+				maxUnavailable = r.MaxUnavailable.StrVal
+			} else {
+				maxUnavailable = int(r.MaxUnavailable.IntVal)
+			}
+		}
+		if r.Pause != nil {
+			pause = r.Pause.Duration
+		}
+		haltOnFailure = ptr.Deref(r.HaltOnFailure, true)
+		drain = ptr.Deref(r.Drain, false)
+		maxConcurrentRestarts = ptr.Deref(r.MaxConcurrentRestarts, maxConcurrentRestarts)
+	}
+
+	return map[string]any{
+		"maxUnavailable": maxUnavailable,
+		// The DaemonSet controller only continues with the next node once the installer pod has been ready for
+		// minReadySeconds.
+		"minReadySeconds":       int64(pause.Seconds()),
+		"haltOnFailure":         haltOnFailure,
+		"drain":                 drain,
+		"maxConcurrentRestarts": maxConcurrentRestarts,
+	}
+}
+
+// nodeSelector returns the node selector for the nodes of the worker pool of the given ContainerRuntime.
+func nodeSelector(cr *extensionsv1alpha1.ContainerRuntime) map[string]string {
+	nodeSelectorValue := map[string]string{
+		extensionsv1alpha1.CRINameWorkerLabel: string(extensionsv1alpha1.CRINameContainerD),
+	}
+
+	for key, value := range cr.Spec.WorkerPool.Selector.MatchLabels {
+		nodeSelectorValue[key] = value
+	}
+
+	return nodeSelectorValue
+}
+
+// runscConfigFlagsOrder is the order in which the typed runsc flags are rendered into runsc.toml.
+var runscConfigFlagsOrder = []string{
+	gvisorconfiguration.FlagPlatform,
+	gvisorconfiguration.FlagNetwork,
+	gvisorconfiguration.FlagOverlay,
+	gvisorconfiguration.FlagFileAccess,
+	gvisorconfiguration.FlagNetRaw,
+	gvisorconfiguration.FlagNVProxy,
+	gvisorconfiguration.FlagDebug,
+	gvisorconfiguration.FlagDebugLog,
+	gvisorconfiguration.FlagPanicSignal,
+}
+
+// renderRunscConfigFlags renders the given runsc flags into the `[runsc_config]` section of `runsc.toml`. A list of all
+// flags supported by runsc can be found here: https://github.com/google/gvisor/blob/master/runsc/config/flags.go
+func renderRunscConfigFlags(flags map[string]string) string {
+	var sb strings.Builder
+	for _, key := range runscConfigFlagsOrder {
+		if value, ok := flags[key]; ok {
+			fmt.Fprintf(&sb, "%s = %q\n", key, value)
+		}
+	}
+	return sb.String()
+}
+
+// RunscConfigFlags returns the typed runsc flags of the given configuration, see Installation.RenderRunscConfigFlags.
+func RunscConfigFlags(providerConfig *gvisorconfiguration.GVisorConfiguration) map[string]string {
+	flags := map[string]string{}
+	// The platform is determined on each node by the installation in `auto` mode.
+	if providerConfig.Platform != nil && *providerConfig.Platform != gvisorconfiguration.PlatformAuto {
+		flags[gvisorconfiguration.FlagPlatform] = string(*providerConfig.Platform)
+	}
+	if providerConfig.Network != nil {
+		flags[gvisorconfiguration.FlagNetwork] = string(*providerConfig.Network)
+	}
+	if providerConfig.Overlay != nil {
+		flags[gvisorconfiguration.FlagOverlay] = *providerConfig.Overlay
+	}
+	if providerConfig.FileAccess != nil {
+		flags[gvisorconfiguration.FlagFileAccess] = string(*providerConfig.FileAccess)
+	}
+	// Booleans are rendered if they are set, including `false`, so that the flags in `runsc.toml` match the configuration.
+	if providerConfig.NetRaw != nil {
+		flags[gvisorconfiguration.FlagNetRaw] = strconv.FormatBool(*providerConfig.NetRaw)
+	}
+	if providerConfig.NVProxy != nil {
+		flags[gvisorconfiguration.FlagNVProxy] = strconv.FormatBool(*providerConfig.NVProxy)
+	}
+	if providerConfig.Debug != nil {
+		flags[gvisorconfiguration.FlagDebug] = strconv.FormatBool(*providerConfig.Debug)
+		if *providerConfig.Debug {
+			flags[gvisorconfiguration.FlagDebugLog] = ptr.Deref(providerConfig.DebugLog, gvisorconfiguration.DefaultDebugLog)
+		}
+	}
+	if providerConfig.PanicSignal != nil {
+		flags[gvisorconfiguration.FlagPanicSignal] = strconv.Itoa(int(*providerConfig.PanicSignal))
+	}
+	return flags
+}
+
+// ProfilesRunscConfigFlags returns the typed runsc flags of the profiles of the given configuration by their name, see
+// RunscConfigFlags. Unset fields of a profile default to the fields of the configuration.
+func ProfilesRunscConfigFlags(providerConfig *gvisorconfiguration.GVisorConfiguration) map[string]map[string]string {
+	if len(providerConfig.Profiles) == 0 {
+		return nil
+	}
+
+	profiles := make(map[string]map[string]string, len(providerConfig.Profiles))
+	for _, profile := range providerConfig.Profiles {
+		profileConfig := providerConfig.DeepCopy()
+		profileConfig.Network = cmp.Or(profile.Network, providerConfig.Network)
+		profileConfig.Overlay = cmp.Or(profile.Overlay, providerConfig.Overlay)
+		profileConfig.FileAccess = cmp.Or(profile.FileAccess, providerConfig.FileAccess)
+		profileConfig.Debug = cmp.Or(profile.Debug, providerConfig.Debug)
+		profileConfig.DebugLog = cmp.Or(profile.DebugLog, providerConfig.DebugLog)
+		profileConfig.NetRaw = cmp.Or(profile.NetRaw, providerConfig.NetRaw)
+		profileConfig.NVProxy = cmp.Or(profile.NVProxy, providerConfig.NVProxy)
+		profileConfig.PanicSignal = cmp.Or(profile.PanicSignal, providerConfig.PanicSignal)
+		profiles[profile.Name] = RunscConfigFlags(profileConfig)
+	}
+	return profiles
+}
+
+// RenderGVisorChart renders the gVisor chart with the `gvisor` RuntimeClass and a RuntimeClass for each of the given
+// profiles. The RuntimeClasses of profiles are only rendered if gVisor is installed by the installation DaemonSet.
+func RenderGVisorChart(renderer chartrenderer.Interface, installationMode gvisor.InstallationMode, profiles []string) ([]byte, error) {
+	runtimeClassNodeSelector := map[string]string{
+		fmt.Sprintf(extensionsv1alpha1.ContainerRuntimeNameWorkerLabel, gvisor.Type): "true",
+	}
+	// Gardener labels the nodes before the installer has finished. Nodes installed via the OperatingSystemConfig have
+	// the binaries and the containerd config in place before containerd starts.
+	if installationMode != gvisor.InstallationModeOperatingSystemConfig {
+		runtimeClassNodeSelector[gvisor.LabelReady] = "true"
+	}
+
+	var profileRuntimeClasses []map[string]any
+	if installationMode != gvisor.InstallationModeOperatingSystemConfig {
+		for _, profile := range profiles {
+			// Profiles are configured per worker pool, the installer labels the nodes on which a profile is configured.
+			nodeSelector := maps.Clone(runtimeClassNodeSelector)
+			nodeSelector[gvisor.LabelPrefixProfile+profile] = "true"
+			profileRuntimeClasses = append(profileRuntimeClasses, map[string]any{
+				"name":         profile,
+				"handler":      nodeinstaller.ProfileRuntimeName(profile),
+				"nodeSelector": nodeSelector,
+			})
+		}
+	}
+
+	gvisorChartValues := map[string]any{
+		"runtimeClass": map[string]any{
+			"nodeSelector": runtimeClassNodeSelector,
+		},
+		"profiles": profileRuntimeClasses,
+	}
 
 	release, err := renderer.RenderEmbeddedFS(charts.InternalChart, gvisor.ChartPath, gvisor.ReleaseName, metav1.NamespaceSystem, gvisorChartValues)
 	if err != nil {
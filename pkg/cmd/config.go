@@ -5,13 +5,21 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
 )
 
 // ConfigOptions are command line options that can be set for the Config.
 type ConfigOptions struct {
 	// InstallationTestRepository is the repository for test images of the gardener-extension-runtime-gvisor-installation container
 	InstallationTestRepository string
+	// ContainerdConfigMode is the mode in which the runsc runtime is configured in containerd on the nodes.
+	ContainerdConfigMode string
+	// InstallationMode is the mode in which gVisor is installed on the nodes.
+	InstallationMode string
 
 	config *Config
 }
@@ -20,6 +28,10 @@ type ConfigOptions struct {
 type Config struct {
 	// InstallationTestRepository is the repository for test images of the gardener-extension-runtime-gvisor-installation container
 	InstallationTestRepository *string
+	// ContainerdConfigMode is the mode in which the runsc runtime is configured in containerd on the nodes.
+	ContainerdConfigMode gvisor.ContainerdConfigMode
+	// InstallationMode is the mode in which gVisor is installed on the nodes.
+	InstallationMode gvisor.InstallationMode
 }
 
 // Complete implements Completer.Complete.
@@ -28,6 +40,20 @@ func (c *ConfigOptions) Complete() error {
 	if c.InstallationTestRepository != "" {
 		c.config.InstallationTestRepository = &c.InstallationTestRepository
 	}
+
+	switch mode := gvisor.ContainerdConfigMode(c.ContainerdConfigMode); mode {
+	case gvisor.ContainerdConfigModeAuto, gvisor.ContainerdConfigModeDropIn, gvisor.ContainerdConfigModeConfigFile:
+		c.config.ContainerdConfigMode = mode
+	default:
+		return fmt.Errorf("unsupported containerd config mode %q", c.ContainerdConfigMode)
+	}
+
+	switch mode := gvisor.InstallationMode(c.InstallationMode); mode {
+	case gvisor.InstallationModeDaemonSet, gvisor.InstallationModeOperatingSystemConfig:
+		c.config.InstallationMode = mode
+	default:
+		return fmt.Errorf("unsupported installation mode %q", c.InstallationMode)
+	}
 	return nil
 }
 
@@ -39,6 +65,8 @@ func (c *ConfigOptions) Completed() *Config {
 // AddFlags implements Flagger.AddFlags.
 func (c *ConfigOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&c.InstallationTestRepository, "gvisor-installation-test-repository", "", "repository with test images of the gardener-extension-runtime-gvisor-installation container")
+	fs.StringVar(&c.ContainerdConfigMode, "gvisor-installation-containerd-config-mode", string(gvisor.ContainerdConfigModeAuto), "mode in which the runsc runtime is configured in containerd on the nodes, one of auto, drop-in or config-file")
+	fs.StringVar(&c.InstallationMode, "gvisor-installation-mode", string(gvisor.InstallationModeDaemonSet), "mode in which gVisor is installed on the nodes, one of daemonset or operatingsystemconfig")
 }
 
 // Apply sets the values of this Config in the given Config.
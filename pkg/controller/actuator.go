@@ -10,10 +10,16 @@ import (
 
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	"github.com/gardener/gardener/extensions/pkg/controller/containerruntime"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/chartrenderer"
 	"github.com/gardener/gardener/pkg/utils/managedresources"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/charts"
 	gvisorcmd "github.com/gardener/gardener-extension-runtime-gvisor/pkg/cmd"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
 )
 
 type actuator struct {
@@ -21,14 +27,16 @@ type actuator struct {
 
 	client client.Client
 	config gvisorcmd.Config
+	clock  clock.Clock
 }
 
 // NewActuator creates a new Actuator that updates the status of the handled ContainerRuntime resources.
-func NewActuator(c client.Client, chartRendererFactory extensionscontroller.ChartRendererFactory, config gvisorcmd.Config) containerruntime.Actuator {
+func NewActuator(c client.Client, chartRendererFactory extensionscontroller.ChartRendererFactory, config gvisorcmd.Config, clock clock.Clock) containerruntime.Actuator {
 	return &actuator{
 		chartRendererFactory: chartRendererFactory,
 		client:               c,
 		config:               config,
+		clock:                clock,
 	}
 }
 
@@ -46,3 +54,49 @@ func (a *actuator) deleteManagedResource(ctx context.Context, namespace, managed
 
 	return nil
 }
+
+// reconcileGVisorManagedResource creates or updates the managed resource with the RuntimeClasses of the shoot.
+// RuntimeClasses are cluster-scoped, hence it contains the RuntimeClasses of the profiles of all gVisor
+// ContainerRuntimes in the namespace which are not being deleted, except for the ContainerRuntime with the given name.
+// The given ContainerRuntime is used instead of the listed one with the same name if it is not nil, e.g. because its
+// provider status has just been updated. Only the profiles of the installations which have been applied to the worker
+// pools according to the provider status are considered, so that pods are not scheduled with the RuntimeClass of a
+// profile whose installation is deferred until the maintenance time window.
+func (a *actuator) reconcileGVisorManagedResource(ctx context.Context, chartRenderer chartrenderer.Interface, namespace, excludeName string, reconciled *extensionsv1alpha1.ContainerRuntime) error {
+	list := &extensionsv1alpha1.ContainerRuntimeList{}
+	if err := a.client.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+
+	containerRuntimes := make([]*extensionsv1alpha1.ContainerRuntime, 0, len(list.Items)+1)
+	for i := range list.Items {
+		if cr := &list.Items[i]; cr.Name != excludeName && (reconciled == nil || cr.Name != reconciled.Name) {
+			containerRuntimes = append(containerRuntimes, cr)
+		}
+	}
+	if reconciled != nil {
+		containerRuntimes = append(containerRuntimes, reconciled)
+	}
+
+	profiles := sets.New[string]()
+	for _, cr := range containerRuntimes {
+		if cr.Spec.Type != gvisor.Type || cr.DeletionTimestamp != nil {
+			continue
+		}
+		installation, err := charts.CurrentInstallation(cr)
+		if err != nil || installation == nil {
+			// The provider status of new ContainerRuntimes is written after their installation has been applied.
+			continue
+		}
+		for name := range installation.Profiles {
+			profiles.Insert(name)
+		}
+	}
+
+	gVisorChart, err := charts.RenderGVisorChart(chartRenderer, a.config.InstallationMode, sets.List(profiles))
+	if err != nil {
+		return err
+	}
+
+	return managedresources.CreateForShoot(ctx, a.client, namespace, GVisorManagedResourceName, "extension-runtime-gvisor", false, map[string][]byte{charts.GVisorConfigKey: gVisorChart})
+}
@@ -6,13 +6,17 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils/managedresources"
 	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/charts"
 	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
 )
 
@@ -23,11 +27,25 @@ func (a *actuator) Delete(ctx context.Context, log logr.Logger, cr *extensionsv1
 		forceDelete                     = cluster != nil && v1beta1helper.ShootNeedsForceDeletion(cluster.Shoot)
 	)
 
+	log.Info("Deleting managed resource due to the deletion of the corresponding ContainerRuntime", "managedResourceName", CanaryManagedResourceName(cr))
+	if err := a.deleteManagedResource(ctx, cr.Namespace, CanaryManagedResourceName(cr), forceDelete); err != nil {
+		return err
+	}
+
 	log.Info("Deleting managed resource due to the deletion of the corresponding ContainerRuntime", "managedResourceName", installationManagedResourceName)
 	if err := a.deleteManagedResource(ctx, cr.Namespace, installationManagedResourceName, forceDelete); err != nil {
 		return err
 	}
 
+	// gardener-node-agent removes the files and units from the nodes if gVisor is installed via the OperatingSystemConfig.
+	if !forceDelete && a.config.InstallationMode != gvisor.InstallationModeOperatingSystemConfig && isNodeCleanupRequired(cr, cluster) {
+		if err := a.cleanupNodes(ctx, log, cr, cluster); err != nil {
+			return fmt.Errorf("could not remove gVisor from the nodes of worker pool %q: %w", cr.Spec.WorkerPool.Name, err)
+		}
+	} else if err := a.deleteManagedResource(ctx, cr.Namespace, GVisorUninstallationManagedResourceName+"-"+cr.Spec.WorkerPool.Name, forceDelete); err != nil {
+		return err
+	}
+
 	// delete the gVisor managed resource if all ContainerRuntime CRDs of type gVisor have a deletion timestamp
 	list := &extensionsv1alpha1.ContainerRuntimeList{}
 	if err := a.client.List(ctx, list, client.InNamespace(cr.Namespace)); err != nil {
@@ -35,6 +53,16 @@ func (a *actuator) Delete(ctx context.Context, log logr.Logger, cr *extensionsv1
 	}
 
 	if isGVisorInstallationRequired(cr.Name, list) {
+		// The RuntimeClasses of profiles which are only configured for the worker pool are removed.
+		if !forceDelete && cluster != nil && cluster.Shoot != nil {
+			chartRenderer, err := a.chartRendererFactory.NewChartRendererForShoot(cluster.Shoot.Spec.Kubernetes.Version)
+			if err != nil {
+				return fmt.Errorf("could not create chart renderer for shoot '%s', %w", cr.Namespace, err)
+			}
+			if err := a.reconcileGVisorManagedResource(ctx, chartRenderer, cr.Namespace, cr.Name, nil); err != nil {
+				return err
+			}
+		}
 		log.Info("gVisor is still required in the cluster - go ahead with ContainerRuntime deletion")
 		return nil
 	}
@@ -43,6 +71,61 @@ func (a *actuator) Delete(ctx context.Context, log logr.Logger, cr *extensionsv1
 	return a.deleteManagedResource(ctx, cr.Namespace, GVisorManagedResourceName, forceDelete)
 }
 
+// cleanupNodes removes gVisor from the nodes of the worker pool with an uninstallation DaemonSet. It waits until the
+// DaemonSet is healthy, i.e. all nodes have been cleaned up, and deletes it afterwards.
+func (a *actuator) cleanupNodes(ctx context.Context, log logr.Logger, cr *extensionsv1alpha1.ContainerRuntime, cluster *extensionscontroller.Cluster) error {
+	chartRenderer, err := a.chartRendererFactory.NewChartRendererForShoot(cluster.Shoot.Spec.Kubernetes.Version)
+	if err != nil {
+		return fmt.Errorf("could not create chart renderer for shoot '%s', %w", cr.Namespace, err)
+	}
+
+	gVisorUninstallationChart, err := charts.RenderGVisorUninstallationChart(chartRenderer, cr)
+	if err != nil {
+		return err
+	}
+
+	managedResourceName := GVisorUninstallationManagedResourceName + "-" + cr.Spec.WorkerPool.Name
+	log.Info("Removing gVisor from the nodes of the worker pool", "managedResourceName", managedResourceName, "workerPoolName", cr.Spec.WorkerPool.Name)
+	if err := managedresources.CreateForShoot(ctx, a.client, cr.Namespace, managedResourceName, "extension-runtime-gvisor", false, map[string][]byte{charts.GVisorConfigKey: gVisorUninstallationChart}); err != nil {
+		return err
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	if err := managedresources.WaitUntilHealthy(timeoutCtx, a.client, cr.Namespace, managedResourceName); err != nil {
+		return fmt.Errorf("uninstallation did not complete: %w", err)
+	}
+
+	log.Info("Removed gVisor from the nodes of the worker pool, deleting managed resource", "managedResourceName", managedResourceName)
+	return a.deleteManagedResource(ctx, cr.Namespace, managedResourceName, false)
+}
+
+// isNodeCleanupRequired returns whether gVisor has to be removed from the nodes of the worker pool. This is only the
+// case if the worker pool still exists and no longer uses gVisor. The nodes of deleted worker pools and shoots are
+// deleted anyway, and the nodes of hibernated shoots are going to be removed.
+func isNodeCleanupRequired(cr *extensionsv1alpha1.ContainerRuntime, cluster *extensionscontroller.Cluster) bool {
+	if cluster == nil || cluster.Shoot == nil || cluster.Shoot.DeletionTimestamp != nil || extensionscontroller.IsHibernationEnabled(cluster) {
+		return false
+	}
+
+	for _, worker := range cluster.Shoot.Spec.Provider.Workers {
+		if worker.Name != cr.Spec.WorkerPool.Name {
+			continue
+		}
+		if worker.CRI == nil {
+			return true
+		}
+		for _, containerRuntime := range worker.CRI.ContainerRuntimes {
+			if containerRuntime.Type == gvisor.Type {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
 func isGVisorInstallationRequired(name string, list *extensionsv1alpha1.ContainerRuntimeList) bool {
 	for _, cr := range list.Items {
 		if cr.Name != name && cr.Spec.Type == gvisor.Type && cr.DeletionTimestamp == nil {
@@ -7,13 +7,21 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/gardener/gardener/pkg/utils/managedresources"
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	gvisorconfig "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+	gvisorconfigv1beta1 "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/v1beta1"
 	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/charts"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
 )
 
 const (
@@ -21,6 +29,10 @@ const (
 	GVisorInstallationManagedResourceName = "extension-runtime-gvisor-installation"
 	// GVisorManagedResourceName is the name of the managed resource.
 	GVisorManagedResourceName = "extension-runtime-gvisor"
+	// GVisorUninstallationManagedResourceName is the name of the managed resource removing gVisor from the nodes.
+	GVisorUninstallationManagedResourceName = "extension-runtime-gvisor-uninstallation"
+	// GVisorCanaryManagedResourceName is the name of the managed resource containing the canary probe of a worker pool.
+	GVisorCanaryManagedResourceName = "extension-runtime-gvisor-canary"
 )
 
 // Reconcile implements ContainerRuntime.Actuator.
@@ -30,30 +42,139 @@ func (a *actuator) Reconcile(ctx context.Context, log logr.Logger, cr *extension
 		return fmt.Errorf("could not create chart renderer for shoot '%s', %w", cr.Namespace, err)
 	}
 
-	log.Info("Preparing gVisor installation", "shoot", cluster.Shoot.Name, "shootNamespace", cluster.Shoot.Namespace)
-	// create MR containing the prerequisites for the installation DaemonSet
-	gVisorChart, err := charts.RenderGVisorChart(chartRenderer)
+	providerConfig, err := charts.DecodeProviderConfig(cr.Spec.ProviderConfig)
+	if err != nil {
+		return err
+	}
+	if err := charts.ValidateInstallationMode(providerConfig, a.config.InstallationMode); err != nil {
+		return err
+	}
+	desired := charts.DesiredInstallation(providerConfig, a.config)
+	if !charts.VersionOffered(providerConfig) {
+		log.Info("Configured gVisor version is not offered anymore, keeping its installation or falling back to the default version",
+			"workerPoolName", cr.Spec.WorkerPool.Name, "version", *providerConfig.Version, "defaultVersion", desired.Version)
+	}
+
+	installation, pending, err := a.installation(log, cr, cluster, providerConfig, desired)
 	if err != nil {
 		return err
 	}
 
-	if err := managedresources.CreateForShoot(ctx, a.client, cr.Namespace, GVisorManagedResourceName, "extension-runtime-gvisor", false, map[string][]byte{charts.GVisorConfigKey: gVisorChart}); err != nil {
+	// The canary probe starts a gVisor sandbox on the nodes of the worker pool periodically, independent of how gVisor
+	// is installed. Its result is reported by the health check, see pkg/healthcheck. It uses the image of the
+	// installation which is rolled out to the nodes, not the one of a deferred change.
+	gVisorCanaryChart, err := charts.RenderGVisorCanaryChart(chartRenderer, cr, a.config, &installation)
+	if err != nil {
 		return err
 	}
 
+	if err := managedresources.CreateForShoot(ctx, a.client, cr.Namespace, CanaryManagedResourceName(cr), "extension-runtime-gvisor", false, map[string][]byte{charts.GVisorConfigKey: gVisorCanaryChart}); err != nil {
+		return err
+	}
+
+	installMRName := fmt.Sprintf("%s-%s", GVisorInstallationManagedResourceName, cr.Spec.WorkerPool.Name)
+
+	if a.config.InstallationMode == gvisor.InstallationModeOperatingSystemConfig {
+		// The installation is part of the OperatingSystemConfig of the worker pool, see pkg/webhook/operatingsystemconfig.
+		// The webhook defers disruptive changes in the same way, so that the provider status describes what is
+		// rolled out to the nodes.
+		log.Info("gVisor is installed via the OperatingSystemConfig, deleting installation managed resource", "managedResourceName", installMRName)
+		if err := managedresources.DeleteForShoot(ctx, a.client, cr.Namespace, installMRName); err != nil {
+			return err
+		}
+		if err := a.updateProviderStatus(ctx, cr, installation, pending); err != nil {
+			return err
+		}
+		return a.reconcileGVisorManagedResource(ctx, chartRenderer, cr.Namespace, "", cr)
+	}
+
 	log.Info("Installing gVisor", "shoot", cluster.Shoot.Name, "shootNamespace", cluster.Shoot.Namespace, "workerPoolName", cr.Spec.WorkerPool.Name)
-	gVisorInstallationChart, err := charts.RenderGVisorInstallationChart(chartRenderer, cr, a.config)
+	gVisorInstallationChart, err := charts.RenderGVisorInstallationChart(chartRenderer, cr, a.config, &installation)
 	if err != nil {
 		return err
 	}
 
 	installSecretName := fmt.Sprintf("%s-%s", GVisorInstallationManagedResourceName, cr.Spec.WorkerPool.Name)
 	secretName, secret := managedresources.NewSecret(a.client, cr.Namespace, installSecretName, map[string][]byte{charts.GVisorConfigKey: gVisorInstallationChart}, true)
-	installMRName := fmt.Sprintf("%s-%s", GVisorInstallationManagedResourceName, cr.Spec.WorkerPool.Name)
 	managedResource := managedresources.NewForShoot(a.client, cr.Namespace, installMRName, "extension-runtime-gvisor", false).WithSecretRef(secretName)
 
 	if err := secret.Reconcile(ctx); err != nil {
 		return err
 	}
-	return managedResource.Reconcile(ctx)
+	if err := managedResource.Reconcile(ctx); err != nil {
+		return err
+	}
+	if err := a.updateProviderStatus(ctx, cr, installation, pending); err != nil {
+		return err
+	}
+
+	log.Info("Deploying gVisor prerequisites and RuntimeClasses", "shoot", cluster.Shoot.Name, "shootNamespace", cluster.Shoot.Namespace)
+	// create MR containing the prerequisites for the installation DaemonSet and the RuntimeClasses of the installations
+	// which have been applied according to the provider status
+	return a.reconcileGVisorManagedResource(ctx, chartRenderer, cr.Namespace, "", cr)
+}
+
+// installation returns the Installation to roll out to the nodes of the worker pool of the given ContainerRuntime and
+// the desired Installation if it is deferred until the maintenance time window of the shoot, see
+// charts.InstallationToRollOut.
+func (a *actuator) installation(log logr.Logger, cr *extensionsv1alpha1.ContainerRuntime, cluster *extensionscontroller.Cluster, providerConfig *gvisorconfig.GVisorConfiguration, desired charts.Installation) (charts.Installation, *charts.Installation, error) {
+	current, err := charts.CurrentInstallation(cr)
+	if err != nil {
+		return charts.Installation{}, nil, err
+	}
+
+	installation, pending := charts.InstallationToRollOut(current, desired, providerConfig, cluster, a.clock)
+	if pending != nil {
+		log.Info("Deferring disruptive change of the gVisor installation until the maintenance time window of the shoot",
+			"workerPoolName", cr.Spec.WorkerPool.Name, "installationImage", installation.Image, "pendingInstallationImage", pending.Image)
+	}
+	return installation, pending, nil
+}
+
+// updateProviderStatus writes the GVisorStatus describing the gVisor installation of the worker pool and the pending
+// installation, if any, to the provider status of the given ContainerRuntime.
+func (a *actuator) updateProviderStatus(ctx context.Context, cr *extensionsv1alpha1.ContainerRuntime, installation charts.Installation, pending *charts.Installation) error {
+	status := &gvisorconfigv1beta1.GVisorStatus{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gvisorconfigv1beta1.SchemeGroupVersion.String(),
+			Kind:       "GVisorStatus",
+		},
+		Version:           installation.Version,
+		InstallationImage: installation.Image,
+		RunscFlags:        installation.RunscConfigFlags,
+		Profiles:          profilesToStatus(installation.Profiles),
+		Platform:          gvisorconfigv1beta1.Platform(installation.Platform),
+	}
+	// The platform is determined on each node in `auto` mode, hence runsc.toml differs between the nodes.
+	if installation.Platform != gvisorconfig.PlatformAuto {
+		status.ConfigHash = gvisor.RunscConfigHash(gvisor.RunscConfig(installation.RenderRunscConfigFlags()))
+	}
+	if pending != nil {
+		status.PendingChange = &gvisorconfigv1beta1.PendingChange{
+			Version:           pending.Version,
+			InstallationImage: pending.Image,
+			Platform:          gvisorconfigv1beta1.Platform(pending.Platform),
+			RunscFlags:        pending.RunscConfigFlags,
+			Profiles:          profilesToStatus(pending.Profiles),
+		}
+	}
+
+	patch := client.MergeFrom(cr.DeepCopy())
+	cr.Status.ProviderStatus = &runtime.RawExtension{Object: status}
+	return a.client.Status().Patch(ctx, cr, patch)
+}
+
+// profilesToStatus returns the runsc flags of the given profiles sorted by the profile name.
+func profilesToStatus(profiles map[string]map[string]string) []gvisorconfigv1beta1.ProfileStatus {
+	var statuses []gvisorconfigv1beta1.ProfileStatus
+	for _, name := range slices.Sorted(maps.Keys(profiles)) {
+		statuses = append(statuses, gvisorconfigv1beta1.ProfileStatus{Name: name, RunscFlags: profiles[name]})
+	}
+	return statuses
+}
+
+// CanaryManagedResourceName returns the name of the managed resource containing the canary probe for the worker pool
+// of the given ContainerRuntime.
+func CanaryManagedResourceName(cr *extensionsv1alpha1.ContainerRuntime) string {
+	return fmt.Sprintf("%s-%s", GVisorCanaryManagedResourceName, cr.Spec.WorkerPool.Name)
 }
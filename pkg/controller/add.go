@@ -12,6 +12,7 @@ import (
 	"github.com/gardener/gardener/extensions/pkg/util"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
@@ -45,7 +46,7 @@ func AddToManagerWithOptions(ctx context.Context, mgr manager.Manager, opts AddO
 	}
 
 	return containerruntime.Add(mgr, containerruntime.AddArgs{
-		Actuator:                  NewActuator(mgr.GetClient(), extensioncontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot), opts.Config),
+		Actuator:                  NewActuator(mgr.GetClient(), extensioncontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot), opts.Config, clock.RealClock{}),
 		ControllerOptions:         opts.Controller,
 		Predicates:                containerruntime.DefaultPredicates(ctx, mgr, opts.IgnoreOperationAnnotation),
 		Type:                      gvisor.Type,
@@ -6,11 +6,15 @@ package controller_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	extensioncontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	"github.com/gardener/gardener/extensions/pkg/controller/containerruntime"
 	"github.com/gardener/gardener/extensions/pkg/util"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
@@ -20,13 +24,18 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testclock "k8s.io/utils/clock/testing"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/gardener/gardener-extension-runtime-gvisor/imagevector"
+	gvisorconfigv1beta1 "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config/v1beta1"
 	gvisorcmd "github.com/gardener/gardener-extension-runtime-gvisor/pkg/cmd"
 	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/controller"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
 )
 
 const (
@@ -47,13 +56,15 @@ var _ = Describe("Controller tests", func() {
 			managedResourceInstallName   string
 			managedResourceInstall       *resourcesv1alpha1.ManagedResource
 			managedResourceInstallSecret *corev1.Secret
+			managedResourceCanary        *resourcesv1alpha1.ManagedResource
 
 			cr2                           *extensionsv1alpha1.ContainerRuntime
 			managedResourceInstall2Name   string
 			managedResourceInstall2       *resourcesv1alpha1.ManagedResource
 			managedResourceInstall2Secret *corev1.Secret
 
-			a containerruntime.Actuator
+			a         containerruntime.Actuator
+			fakeClock *testclock.FakeClock
 
 			log = logf.Log.WithName("test")
 
@@ -73,8 +84,9 @@ var _ = Describe("Controller tests", func() {
 
 		BeforeEach(func() {
 			ctx = context.TODO()
-			c = fake.NewClientBuilder().WithScheme(kubernetes.SeedScheme).Build()
-			a = controller.NewActuator(c, extensioncontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot), gvisorcmd.Config{})
+			c = fake.NewClientBuilder().WithScheme(kubernetes.SeedScheme).WithStatusSubresource(&extensionsv1alpha1.ContainerRuntime{}).Build()
+			fakeClock = testclock.NewFakeClock(time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+			a = controller.NewActuator(c, extensioncontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot), gvisorcmd.Config{}, fakeClock)
 
 			managedResourceName = "extension-runtime-gvisor"
 			managedResource = &resourcesv1alpha1.ManagedResource{
@@ -117,6 +129,13 @@ var _ = Describe("Controller tests", func() {
 				},
 			}
 
+			managedResourceCanary = &resourcesv1alpha1.ManagedResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("extension-runtime-gvisor-canary-%s", cr.Spec.WorkerPool.Name),
+					Namespace: namespaceName,
+				},
+			}
+
 			cr2 = &extensionsv1alpha1.ContainerRuntime{
 				ObjectMeta: metav1.ObjectMeta{Namespace: namespaceName, Name: "test-cr-2"},
 				Spec: extensionsv1alpha1.ContainerRuntimeSpec{
@@ -160,6 +179,8 @@ var _ = Describe("Controller tests", func() {
 			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceInstallSecret), managedResourceInstallSecret)).To(Succeed())
 			Expect(managedResourceInstallSecret.Immutable).To(Equal(pointer.Bool(true)))
 			Expect(managedResourceInstallSecret.Data).To(HaveLen(1))
+
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceCanary), managedResourceCanary)).To(Succeed())
 		}
 
 		deployOnTwoWorkerPools := func() {
@@ -187,6 +208,13 @@ var _ = Describe("Controller tests", func() {
 			Expect(managedResourceInstall2Secret.Data).To(HaveLen(1))
 		}
 
+		runtimeClassManifest := func() string {
+			ExpectWithOffset(1, c.Get(ctx, client.ObjectKeyFromObject(managedResource), managedResource)).To(Succeed())
+			managedResourceSecret.Name = managedResource.Spec.SecretRefs[0].Name
+			ExpectWithOffset(1, c.Get(ctx, client.ObjectKeyFromObject(managedResourceSecret), managedResourceSecret)).To(Succeed())
+			return string(managedResourceSecret.Data["config.yaml"])
+		}
+
 		It("Should successfully install gvisor to a single worker pool", func() {
 			deployOnSingleWorkerPool()
 		})
@@ -195,6 +223,306 @@ var _ = Describe("Controller tests", func() {
 			deployOnTwoWorkerPools()
 		})
 
+		It("Should report the gvisor installation in the provider status", func() {
+			cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","network":"host","netRaw":true}`)}
+			deployOnSingleWorkerPool()
+
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(cr), cr)).To(Succeed())
+			Expect(cr.Status.ProviderStatus).NotTo(BeNil())
+
+			status := &gvisorconfigv1beta1.GVisorStatus{}
+			Expect(json.Unmarshal(cr.Status.ProviderStatus.Raw, status)).To(Succeed())
+			Expect(status.APIVersion).To(Equal("gvisor.runtime.extensions.config.gardener.cloud/v1beta1"))
+			Expect(status.Kind).To(Equal("GVisorStatus"))
+			Expect(status.Version).To(Equal(gvisor.Version))
+			Expect(status.InstallationImage).To(Equal(imagevector.FindImage(gvisor.RuntimeGVisorInstallationImageName, "")))
+			Expect(status.RunscFlags).To(Equal(map[string]string{"network": "host", "net-raw": "true"}))
+			Expect(status.ConfigHash).To(Equal(gvisor.RunscConfigHash([]byte("[runsc_config]\nnetwork = \"host\"\nnet-raw = \"true\"\n"))))
+		})
+
+		It("Should not report the config hash in the provider status if the platform is determined on the nodes", func() {
+			cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","platform":"auto"}`)}
+			deployOnSingleWorkerPool()
+
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(cr), cr)).To(Succeed())
+			status := &gvisorconfigv1beta1.GVisorStatus{}
+			Expect(json.Unmarshal(cr.Status.ProviderStatus.Raw, status)).To(Succeed())
+			Expect(status.RunscFlags).To(BeEmpty())
+			Expect(status.ConfigHash).To(BeEmpty())
+		})
+
+		It("Should report the profiles in the provider status", func() {
+			cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","network":"host","profiles":[{"name":"gvisor-sandbox","network":"sandbox"},{"name":"gvisor-netraw","netRaw":true}]}`)}
+			deployOnSingleWorkerPool()
+
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(cr), cr)).To(Succeed())
+			status := &gvisorconfigv1beta1.GVisorStatus{}
+			Expect(json.Unmarshal(cr.Status.ProviderStatus.Raw, status)).To(Succeed())
+			Expect(status.RunscFlags).To(Equal(map[string]string{"network": "host"}))
+			Expect(status.Profiles).To(Equal([]gvisorconfigv1beta1.ProfileStatus{
+				{Name: "gvisor-netraw", RunscFlags: map[string]string{"network": "host", "net-raw": "true"}},
+				{Name: "gvisor-sandbox", RunscFlags: map[string]string{"network": "sandbox"}},
+			}))
+		})
+
+		It("Should deploy a RuntimeClass for the profiles of all worker pools", func() {
+			cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","profiles":[{"name":"gvisor-netraw","netRaw":true}]}`)}
+			cr2.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","profiles":[{"name":"gvisor-debug","debug":true}]}`)}
+			deployOnTwoWorkerPools()
+
+			manifest := runtimeClassManifest()
+			Expect(manifest).To(ContainSubstring("name: gvisor\n"))
+			Expect(manifest).To(ContainSubstring("name: gvisor-netraw\n"))
+			Expect(manifest).To(ContainSubstring("handler: runsc-gvisor-netraw\n"))
+			Expect(manifest).To(ContainSubstring("profile.gvisor.runtime.extensions.gardener.cloud/gvisor-netraw: \"true\""))
+			Expect(manifest).To(ContainSubstring("name: gvisor-debug\n"))
+			Expect(manifest).To(ContainSubstring("handler: runsc-gvisor-debug\n"))
+
+			Expect(a.Delete(ctx, log, cr, cluster)).To(Succeed())
+
+			manifest = runtimeClassManifest()
+			Expect(manifest).To(ContainSubstring("name: gvisor\n"))
+			Expect(manifest).NotTo(ContainSubstring("gvisor-netraw"))
+			Expect(manifest).To(ContainSubstring("name: gvisor-debug\n"))
+		})
+
+		It("Should fail with a configuration problem for profiles if gvisor is installed via the OperatingSystemConfig", func() {
+			a = controller.NewActuator(c, extensioncontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot), gvisorcmd.Config{InstallationMode: gvisor.InstallationModeOperatingSystemConfig}, fakeClock)
+			cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","profiles":[{"name":"gvisor-netraw","netRaw":true}]}`)}
+			Expect(c.Create(ctx, cr)).To(Succeed())
+
+			err := a.Reconcile(ctx, log, cr, cluster)
+			Expect(err).To(MatchError(ContainSubstring("providerConfig.profiles: Forbidden: not supported if gVisor is installed via the OperatingSystemConfig")))
+
+			var coder v1beta1helper.Coder
+			Expect(errors.As(err, &coder)).To(BeTrue())
+			Expect(coder.Codes()).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+		})
+
+		Describe("maintenance time window", func() {
+			var clusterWithMaintenance *extensioncontroller.Cluster
+
+			providerStatus := func() *gvisorconfigv1beta1.GVisorStatus {
+				ExpectWithOffset(1, c.Get(ctx, client.ObjectKeyFromObject(cr), cr)).To(Succeed())
+				status := &gvisorconfigv1beta1.GVisorStatus{}
+				ExpectWithOffset(1, json.Unmarshal(cr.Status.ProviderStatus.Raw, status)).To(Succeed())
+				return status
+			}
+
+			installationManifest := func() string {
+				ExpectWithOffset(1, c.Get(ctx, client.ObjectKeyFromObject(managedResourceInstall), managedResourceInstall)).To(Succeed())
+				managedResourceInstallSecret.Name = managedResourceInstall.Spec.SecretRefs[0].Name
+				ExpectWithOffset(1, c.Get(ctx, client.ObjectKeyFromObject(managedResourceInstallSecret), managedResourceInstallSecret)).To(Succeed())
+				return string(managedResourceInstallSecret.Data["config.yaml"])
+			}
+
+			canaryManifest := func() string {
+				ExpectWithOffset(1, c.Get(ctx, client.ObjectKeyFromObject(managedResourceCanary), managedResourceCanary)).To(Succeed())
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespaceName, Name: managedResourceCanary.Spec.SecretRefs[0].Name}}
+				ExpectWithOffset(1, c.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+				return string(secret.Data["config.yaml"])
+			}
+
+			BeforeEach(func() {
+				clusterWithMaintenance = &extensioncontroller.Cluster{Shoot: cluster.Shoot.DeepCopy()}
+				clusterWithMaintenance.Shoot.Spec.Maintenance = &gardencorev1beta1.Maintenance{
+					TimeWindow: &gardencorev1beta1.MaintenanceTimeWindow{Begin: "220000+0000", End: "230000+0000"},
+				}
+
+				cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","network":"host"}`)}
+				Expect(c.Create(ctx, cr)).To(Succeed())
+			})
+
+			It("Should install a new worker pool right away", func() {
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				status := providerStatus()
+				Expect(status.RunscFlags).To(Equal(map[string]string{"network": "host"}))
+				Expect(status.Platform).To(Equal(gvisorconfigv1beta1.PlatformSystrap))
+				Expect(status.PendingChange).To(BeNil())
+			})
+
+			It("Should defer disruptive changes until the maintenance time window", func() {
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","network":"sandbox","rollout":{"maxUnavailable":2}}`)}
+				Expect(c.Update(ctx, cr)).To(Succeed())
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				status := providerStatus()
+				Expect(status.RunscFlags).To(Equal(map[string]string{"network": "host"}))
+				Expect(status.ConfigHash).To(Equal(gvisor.RunscConfigHash([]byte("[runsc_config]\nnetwork = \"host\"\n"))))
+				Expect(status.PendingChange).To(Equal(&gvisorconfigv1beta1.PendingChange{
+					Version:           gvisor.Version,
+					InstallationImage: imagevector.FindImage(gvisor.RuntimeGVisorInstallationImageName, ""),
+					Platform:          gvisorconfigv1beta1.PlatformSystrap,
+					RunscFlags:        map[string]string{"network": "sandbox"},
+				}))
+				// Non-disruptive changes are rolled out right away.
+				manifest := installationManifest()
+				Expect(manifest).To(ContainSubstring(`network = "host"`))
+				Expect(manifest).To(ContainSubstring("maxUnavailable: 2"))
+
+				fakeClock.SetTime(time.Date(2026, 10, 18, 22, 30, 0, 0, time.UTC))
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				status = providerStatus()
+				Expect(status.RunscFlags).To(Equal(map[string]string{"network": "sandbox"}))
+				Expect(status.PendingChange).To(BeNil())
+				Expect(installationManifest()).To(ContainSubstring(`network = "sandbox"`))
+			})
+
+			It("Should defer disruptive changes until the maintenance time window if gvisor is installed via the OperatingSystemConfig", func() {
+				a = controller.NewActuator(c, extensioncontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot), gvisorcmd.Config{InstallationMode: gvisor.InstallationModeOperatingSystemConfig}, fakeClock)
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","network":"sandbox"}`)}
+				Expect(c.Update(ctx, cr)).To(Succeed())
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				status := providerStatus()
+				Expect(status.RunscFlags).To(Equal(map[string]string{"network": "host"}))
+				Expect(status.PendingChange).NotTo(BeNil())
+				Expect(status.PendingChange.RunscFlags).To(Equal(map[string]string{"network": "sandbox"}))
+
+				fakeClock.SetTime(time.Date(2026, 10, 18, 22, 30, 0, 0, time.UTC))
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				status = providerStatus()
+				Expect(status.RunscFlags).To(Equal(map[string]string{"network": "sandbox"}))
+				Expect(status.PendingChange).To(BeNil())
+			})
+
+			It("Should probe the installed image with the canary until the maintenance time window", func() {
+				cr.Status.ProviderStatus = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorStatus","version":"` + gvisor.Version + `","installationImage":"example.com/runtime-gvisor-installation:v0.1.0","platform":"systrap","runscFlags":{"network":"host"}}`)}
+				Expect(c.Status().Update(ctx, cr)).To(Succeed())
+
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				Expect(providerStatus().PendingChange).NotTo(BeNil())
+				Expect(installationManifest()).To(ContainSubstring("example.com/runtime-gvisor-installation:v0.1.0"))
+				Expect(canaryManifest()).To(ContainSubstring("example.com/runtime-gvisor-installation:v0.1.0"))
+
+				fakeClock.SetTime(time.Date(2026, 10, 18, 22, 30, 0, 0, time.UTC))
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				Expect(providerStatus().PendingChange).To(BeNil())
+				Expect(canaryManifest()).To(ContainSubstring(imagevector.FindImage(gvisor.RuntimeGVisorInstallationImageName, "")))
+			})
+
+			It("Should defer changes of profiles until the maintenance time window", func() {
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","network":"host","profiles":[{"name":"gvisor-netraw","netRaw":true}]}`)}
+				Expect(c.Update(ctx, cr)).To(Succeed())
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				status := providerStatus()
+				Expect(status.Profiles).To(BeEmpty())
+				Expect(status.PendingChange).NotTo(BeNil())
+				Expect(status.PendingChange.Profiles).To(Equal([]gvisorconfigv1beta1.ProfileStatus{
+					{Name: "gvisor-netraw", RunscFlags: map[string]string{"network": "host", "net-raw": "true"}},
+				}))
+				Expect(installationManifest()).NotTo(ContainSubstring("runsc-profiles"))
+				// The RuntimeClass of the profile is only deployed once its installation is applied.
+				Expect(runtimeClassManifest()).NotTo(ContainSubstring("gvisor-netraw"))
+
+				fakeClock.SetTime(time.Date(2026, 10, 18, 22, 30, 0, 0, time.UTC))
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				status = providerStatus()
+				Expect(status.Profiles).To(HaveLen(1))
+				Expect(status.PendingChange).To(BeNil())
+				Expect(installationManifest()).To(ContainSubstring("runsc-profiles"))
+				Expect(runtimeClassManifest()).To(ContainSubstring("name: gvisor-netraw\n"))
+
+				// The installed profiles are not reported as a change.
+				fakeClock.SetTime(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+				Expect(providerStatus().PendingChange).To(BeNil())
+			})
+
+			It("Should roll out disruptive changes right away if the shoot is hibernated", func() {
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				clusterWithMaintenance.Shoot.Spec.Hibernation = &gardencorev1beta1.Hibernation{Enabled: pointer.Bool(true)}
+				clusterWithMaintenance.Shoot.Status.IsHibernated = true
+				cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","network":"sandbox"}`)}
+				Expect(c.Update(ctx, cr)).To(Succeed())
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				status := providerStatus()
+				Expect(status.RunscFlags).To(Equal(map[string]string{"network": "sandbox"}))
+				Expect(status.PendingChange).To(BeNil())
+			})
+
+			It("Should not defer unchanged installations reported by previous versions", func() {
+				cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","platform":"auto"}`)}
+				Expect(c.Update(ctx, cr)).To(Succeed())
+				// Previous versions did not report the platform.
+				cr.Status.ProviderStatus = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorStatus","installationImage":"` + imagevector.FindImage(gvisor.RuntimeGVisorInstallationImageName, "") + `"}`)}
+				Expect(c.Status().Update(ctx, cr)).To(Succeed())
+
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				status := providerStatus()
+				Expect(status.Platform).To(Equal(gvisorconfigv1beta1.PlatformAuto))
+				Expect(status.PendingChange).To(BeNil())
+			})
+
+			It("Should keep the installation of a version which is not offered anymore", func() {
+				cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","network":"host","version":"20200101.0"}`)}
+				Expect(c.Update(ctx, cr)).To(Succeed())
+				cr.Status.ProviderStatus = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorStatus","version":"20200101.0","installationImage":"example.com/runtime-gvisor-installation:v0.1.0","platform":"systrap","runscFlags":{"network":"host"}}`)}
+				Expect(c.Status().Update(ctx, cr)).To(Succeed())
+
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				status := providerStatus()
+				Expect(status.Version).To(Equal("20200101.0"))
+				Expect(status.InstallationImage).To(Equal("example.com/runtime-gvisor-installation:v0.1.0"))
+				Expect(status.PendingChange).To(BeNil())
+				Expect(installationManifest()).To(ContainSubstring("example.com/runtime-gvisor-installation:v0.1.0"))
+			})
+
+			It("Should fall back to the default version if a version which is not offered anymore is not installed", func() {
+				cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","network":"host","version":"20200101.0"}`)}
+				Expect(c.Update(ctx, cr)).To(Succeed())
+
+				Expect(a.Reconcile(ctx, log, cr, clusterWithMaintenance)).To(Succeed())
+
+				status := providerStatus()
+				Expect(status.Version).To(Equal(gvisor.Version))
+				Expect(status.InstallationImage).To(Equal(imagevector.FindImage(gvisor.RuntimeGVisorInstallationImageName, "")))
+			})
+		})
+
+		It("Should fail with a configuration problem if the provider config is invalid", func() {
+			cr.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1alpha1","kind":"GVisorConfiguration","configFlags":{"net-raw":"yes"}}`)}
+			Expect(c.Create(ctx, cr)).To(Succeed())
+
+			err := a.Reconcile(ctx, log, cr, cluster)
+			Expect(err).To(MatchError(ContainSubstring(`providerConfig.configFlags[net-raw]: Invalid value: "yes"`)))
+
+			var coder v1beta1helper.Coder
+			Expect(errors.As(err, &coder)).To(BeTrue())
+			Expect(coder.Codes()).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceInstall), managedResourceInstall)).To(BeNotFoundError())
+		})
+
+		It("Should not deploy the installation DaemonSet if gvisor is installed via the OperatingSystemConfig", func() {
+			deployOnSingleWorkerPool()
+
+			a = controller.NewActuator(c, extensioncontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot), gvisorcmd.Config{InstallationMode: gvisor.InstallationModeOperatingSystemConfig}, fakeClock)
+			Expect(a.Reconcile(ctx, log, cr, cluster)).To(Succeed())
+
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResource), managedResource)).To(Succeed())
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceInstall), managedResourceInstall)).To(BeNotFoundError())
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceInstallSecret), managedResourceInstallSecret)).To(BeNotFoundError())
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceCanary), managedResourceCanary)).To(Succeed())
+		})
+
 		It("Should successfully delete gvisor managed resources", func() {
 			deployOnSingleWorkerPool()
 
@@ -205,6 +533,8 @@ var _ = Describe("Controller tests", func() {
 
 			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceInstall), managedResourceInstall)).To(BeNotFoundError())
 			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceInstallSecret), managedResourceInstallSecret)).To(BeNotFoundError())
+
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceCanary), managedResourceCanary)).To(BeNotFoundError())
 		})
 
 		It("Should successfully delete only one of the gvisor installations", func() {
@@ -221,5 +551,45 @@ var _ = Describe("Controller tests", func() {
 			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceInstall2), managedResourceInstall2)).To(Succeed())
 			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceInstall2Secret), managedResourceInstall2Secret)).To(Succeed())
 		})
+
+		It("Should remove gvisor from the nodes of a worker pool which no longer uses it", func() {
+			deployOnSingleWorkerPool()
+
+			clusterWithWorkers := &extensioncontroller.Cluster{Shoot: cluster.Shoot.DeepCopy()}
+			clusterWithWorkers.Shoot.Spec.Provider.Workers = []gardencorev1beta1.Worker{{
+				Name: workerGroup,
+				CRI:  &gardencorev1beta1.CRI{Name: gardencorev1beta1.CRINameContainerD},
+			}}
+
+			// The uninstallation DaemonSet has completed on all nodes.
+			managedResourceUninstall := &resourcesv1alpha1.ManagedResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "extension-runtime-gvisor-uninstallation-" + workerGroup, Namespace: namespaceName},
+				Status: resourcesv1alpha1.ManagedResourceStatus{Conditions: []gardencorev1beta1.Condition{
+					{Type: resourcesv1alpha1.ResourcesApplied, Status: gardencorev1beta1.ConditionTrue},
+					{Type: resourcesv1alpha1.ResourcesHealthy, Status: gardencorev1beta1.ConditionTrue},
+				}},
+			}
+			Expect(c.Create(ctx, managedResourceUninstall)).To(Succeed())
+
+			Expect(a.Delete(ctx, log, cr, clusterWithWorkers)).To(Succeed())
+
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceInstall), managedResourceInstall)).To(BeNotFoundError())
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResourceUninstall), managedResourceUninstall)).To(BeNotFoundError())
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(managedResource), managedResource)).To(BeNotFoundError())
+		})
+
+		It("Should not remove gvisor from the nodes of a hibernated shoot", func() {
+			deployOnSingleWorkerPool()
+
+			clusterWithWorkers := &extensioncontroller.Cluster{Shoot: cluster.Shoot.DeepCopy()}
+			clusterWithWorkers.Shoot.Spec.Provider.Workers = []gardencorev1beta1.Worker{{Name: workerGroup}}
+			clusterWithWorkers.Shoot.Spec.Hibernation = &gardencorev1beta1.Hibernation{Enabled: pointer.Bool(true)}
+
+			Expect(a.Delete(ctx, log, cr, clusterWithWorkers)).To(Succeed())
+
+			managedResourceList := &resourcesv1alpha1.ManagedResourceList{}
+			Expect(c.List(ctx, managedResourceList, client.InNamespace(namespaceName))).To(Succeed())
+			Expect(managedResourceList.Items).To(BeEmpty())
+		})
 	})
 })
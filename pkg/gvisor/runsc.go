@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gvisor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// RunscConfig returns the content of runsc.toml with the given rendered flags of the `[runsc_config]` section.
+func RunscConfig(configFlags string) []byte {
+	var sb strings.Builder
+	sb.WriteString("[runsc_config]\n")
+	sb.WriteString(configFlags)
+	if configFlags != "" && !strings.HasSuffix(configFlags, "\n") {
+		sb.WriteString("\n")
+	}
+	return []byte(sb.String())
+}
+
+// RunscConfigHash returns the hash of the given runsc config, as it is reported in the AnnotationConfigHash
+// annotation of the nodes.
+func RunscConfigHash(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
@@ -19,13 +19,84 @@ const (
 
 	// InstallationReleaseName is the name of the gVisor installation chart
 	InstallationReleaseName = "gvisor-installation"
+	// UninstallationReleaseName is the name of the gVisor uninstallation chart
+	UninstallationReleaseName = "gvisor-uninstallation"
 	// ReleaseName is the name of the gVisor chart
 	ReleaseName = "gvisor"
+	// CanaryReleaseName is the name of the gVisor canary chart
+	CanaryReleaseName = "gvisor-canary"
+
+	// RuntimeClassName is the name of the RuntimeClass for the default runsc configuration of the worker pools. The
+	// RuntimeClasses of profiles are named after the profiles.
+	RuntimeClassName = "gvisor"
+
+	// LabelPlatform is the label on nodes which contains the gVisor platform used on the node.
+	LabelPlatform = "gvisor.runtime.extensions.gardener.cloud/platform"
+	// LabelReady is the label on nodes which is set to "true" by the node installer once gVisor has been installed and
+	// containerd has been verified to serve the runsc runtime. The gvisor RuntimeClass requires it.
+	LabelReady = "gvisor.runtime.extensions.gardener.cloud/ready"
+	// LabelPrefixProfile is the prefix of the labels on nodes which are set to "true" by the node installer for each
+	// profile configured on the node, e.g. `profile.gvisor.runtime.extensions.gardener.cloud/gvisor-netraw`. The
+	// RuntimeClass of a profile requires its label.
+	LabelPrefixProfile = "profile.gvisor.runtime.extensions.gardener.cloud/"
+	// AnnotationRunscVersion is the annotation on nodes which contains the version of the installed runsc binary. It
+	// is set once gVisor has been installed on the node.
+	AnnotationRunscVersion = "gvisor.runtime.extensions.gardener.cloud/runsc-version"
+	// AnnotationConfigHash is the annotation on nodes which contains the hash of the runsc config of the node.
+	AnnotationConfigHash = "gvisor.runtime.extensions.gardener.cloud/config-hash"
+	// AnnotationInstallationError is the annotation on nodes which contains the error of the last failed installation.
+	// It is removed once gVisor has been installed on the node.
+	AnnotationInstallationError = "gvisor.runtime.extensions.gardener.cloud/installation-error"
+	// AnnotationDriftCount is the annotation on nodes which contains the number of times the node installer repaired
+	// installed binaries or config files which have been changed or removed on the node.
+	AnnotationDriftCount = "gvisor.runtime.extensions.gardener.cloud/drift-count"
+	// AnnotationOutdatedSandboxes is the annotation on nodes which contains the number of running sandboxes which still
+	// use replaced containerd-shim-runsc-v1 or runsc binaries, e.g. of a previous gVisor release, and have to be
+	// restarted to use the installed ones.
+	AnnotationOutdatedSandboxes = "gvisor.runtime.extensions.gardener.cloud/outdated-sandboxes"
+	// AnnotationTamperedBinaries is the annotation on nodes which contains the comma-separated paths of installed
+	// binaries which have been modified on the node, i.e. which did not match the checksums they had been installed
+	// with. The binaries are reinstalled, the annotation is kept until it is removed by an operator.
+	AnnotationTamperedBinaries = "gvisor.runtime.extensions.gardener.cloud/tampered-binaries"
+
+	// TaintKeyNotReady is the key of the taint with effect NoSchedule which new nodes of worker pools with enabled
+	// startup taint are registered with. It is removed by the node installer once gVisor has been installed and
+	// verified on the node.
+	TaintKeyNotReady = "gvisor.runtime.extensions.gardener.cloud/not-ready"
+)
+
+// ContainerdConfigMode is the mode in which the runsc runtime is configured in containerd on the nodes.
+type ContainerdConfigMode string
+
+const (
+	// ContainerdConfigModeAuto uses a drop-in file if the containerd config of the node imports it, otherwise the
+	// containerd config file is modified.
+	ContainerdConfigModeAuto ContainerdConfigMode = "auto"
+	// ContainerdConfigModeDropIn configures the runtime in a drop-in file which must be imported by the containerd
+	// config of the node.
+	ContainerdConfigModeDropIn ContainerdConfigMode = "drop-in"
+	// ContainerdConfigModeConfigFile configures the runtime in the containerd config file of the node.
+	ContainerdConfigModeConfigFile ContainerdConfigMode = "config-file"
+)
+
+// InstallationMode is the mode in which gVisor is installed on the nodes.
+type InstallationMode string
+
+const (
+	// InstallationModeDaemonSet installs gVisor with a privileged DaemonSet in the shoot.
+	InstallationModeDaemonSet InstallationMode = "daemonset"
+	// InstallationModeOperatingSystemConfig adds the binaries and the runtime configuration to the
+	// OperatingSystemConfig of the worker pool, so that they are in place when the node boots.
+	InstallationModeOperatingSystemConfig InstallationMode = "operatingsystemconfig"
 )
 
 var (
 	// InstallationChartPath path for internal GVisor installation Chart
 	InstallationChartPath = filepath.Join(charts.InternalChartsPath, "gvisor-installation")
+	// UninstallationChartPath path for internal GVisor uninstallation Chart
+	UninstallationChartPath = filepath.Join(charts.InternalChartsPath, "gvisor-uninstallation")
 	// ChartPath is the path for internal GVisor Chart.
 	ChartPath = filepath.Join(charts.InternalChartsPath, "gvisor")
+	// CanaryChartPath is the path for internal GVisor canary Chart.
+	CanaryChartPath = filepath.Join(charts.InternalChartsPath, "gvisor-canary")
 )
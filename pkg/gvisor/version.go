@@ -0,0 +1,9 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gvisor
+
+// Version is the version of the gVisor release whose binaries are bundled in the installation image. It is set at
+// build time from the GVISOR_VERSION file, see Makefile.
+var Version = ""
@@ -5,10 +5,12 @@
 package healthcheck
 
 import (
+	"context"
 	"time"
 
 	healthcheckconfig "github.com/gardener/gardener/extensions/pkg/apis/config/v1alpha1"
 	"github.com/gardener/gardener/extensions/pkg/controller/healthcheck"
+	"github.com/gardener/gardener/extensions/pkg/controller/healthcheck/general"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -16,6 +18,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	gvisorcmd "github.com/gardener/gardener-extension-runtime-gvisor/pkg/cmd"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/controller"
 	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
 )
 
@@ -25,6 +29,8 @@ var (
 	AddOptions = healthcheck.DefaultAddArgs{
 		HealthCheckConfig: healthcheckconfig.HealthCheckConfig{SyncPeriod: metav1.Duration{Duration: defaultSyncPeriod}},
 	}
+	// Config is the configuration of the extension the health checks are registered for.
+	Config gvisorcmd.Config
 )
 
 // RegisterHealthChecks adds a controller with the given Options to the manager.
@@ -38,11 +44,46 @@ func RegisterHealthChecks(mgr manager.Manager, opts healthcheck.DefaultAddArgs)
 		mgr,
 		opts,
 		nil,
-		[]healthcheck.ConditionTypeToHealthCheck{},
-		sets.New[gardencorev1beta1.ConditionType](),
+		[]healthcheck.ConditionTypeToHealthCheck{
+			{
+				ConditionType: string(gardencorev1beta1.ShootSystemComponentsHealthy),
+				HealthCheck:   general.CheckManagedResource(controller.GVisorManagedResourceName),
+			},
+			{
+				ConditionType: string(gardencorev1beta1.ShootSystemComponentsHealthy),
+				PreCheckFunc:  isInstalledByDaemonSet,
+				HealthCheck:   NewInstallationManagedResourceHealthChecker(),
+			},
+			{
+				ConditionType: string(gardencorev1beta1.ShootSystemComponentsHealthy),
+				PreCheckFunc:  isInstalledByDaemonSet,
+				HealthCheck:   NewInstallationDaemonSetHealthChecker(),
+			},
+			{
+				ConditionType: string(gardencorev1beta1.ShootSystemComponentsHealthy),
+				PreCheckFunc:  isInstalledByDaemonSet,
+				HealthCheck:   NewNodesHealthChecker(),
+			},
+			{
+				ConditionType: ConditionTypeSandboxHealthy,
+				HealthCheck:   NewCanaryHealthChecker(),
+			},
+			{
+				ConditionType: ConditionTypeSandboxesUpToDate,
+				PreCheckFunc:  isInstalledByDaemonSet,
+				HealthCheck:   NewOutdatedSandboxesHealthChecker(),
+			},
+		},
+		sets.New(gardencorev1beta1.ShootSystemComponentsHealthy, ConditionTypeSandboxHealthy, ConditionTypeSandboxesUpToDate),
 	)
 }
 
+// isInstalledByDaemonSet returns true if gVisor is installed on the nodes by the installation DaemonSet, i.e. if the
+// installation mode of the configured Config is not the OperatingSystemConfig. It does not read the cluster.
+func isInstalledByDaemonSet(_ context.Context, _ client.Client, _ client.Object, _ any) bool {
+	return Config.InstallationMode != gvisor.InstallationModeOperatingSystemConfig
+}
+
 // AddToManager adds a controller with the default Options.
 func AddToManager(mgr manager.Manager) error {
 	return RegisterHealthChecks(mgr, AddOptions)
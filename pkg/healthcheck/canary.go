@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gardener/gardener/extensions/pkg/controller/healthcheck"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// ConditionTypeSandboxHealthy is the type of the condition on the ContainerRuntime which reports whether gVisor
+	// sandboxes can be started on the nodes of the worker pool.
+	ConditionTypeSandboxHealthy = "SandboxHealthy"
+	// CanaryProgressingThreshold is the duration after which a canary probe which has not completed yet, e.g. because
+	// the CronJob has just been created, is reported as unhealthy.
+	CanaryProgressingThreshold = 15 * time.Minute
+)
+
+// CanaryLatency is the duration of the latest successful canary probe of a worker pool, i.e. the time from the start
+// of its Job until the gVisor sandbox has been started and has exited, by the namespace of the shoot and the name of
+// the worker pool.
+var CanaryLatency = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gardener_extension_runtime_gvisor_canary_latency_seconds",
+	Help: "Duration of the latest successful gVisor canary probe of a worker pool in seconds.",
+}, []string{"namespace", "worker_pool"})
+
+func init() {
+	metrics.Registry.MustRegister(CanaryLatency)
+}
+
+// CanaryHealthChecker checks the result of the latest canary probe starting a gVisor sandbox on the nodes of the
+// worker pool of a ContainerRuntime.
+type CanaryHealthChecker struct {
+	logger       logr.Logger
+	sourceClient client.Client
+	targetClient client.Client
+}
+
+var (
+	_ healthcheck.HealthCheck  = (*CanaryHealthChecker)(nil)
+	_ healthcheck.SourceClient = (*CanaryHealthChecker)(nil)
+	_ healthcheck.TargetClient = (*CanaryHealthChecker)(nil)
+)
+
+// NewCanaryHealthChecker creates a new CanaryHealthChecker.
+func NewCanaryHealthChecker() *CanaryHealthChecker {
+	return &CanaryHealthChecker{}
+}
+
+// InjectSourceClient injects the seed client.
+func (h *CanaryHealthChecker) InjectSourceClient(sourceClient client.Client) {
+	h.sourceClient = sourceClient
+}
+
+// InjectTargetClient injects the shoot client.
+func (h *CanaryHealthChecker) InjectTargetClient(targetClient client.Client) {
+	h.targetClient = targetClient
+}
+
+// SetLoggerSuffix injects the logger.
+func (h *CanaryHealthChecker) SetLoggerSuffix(provider, extension string) {
+	h.logger = log.Log.WithName("healthcheck-gvisor-canary").WithValues("provider", provider, "extension", extension)
+}
+
+// Check executes the health check.
+func (h *CanaryHealthChecker) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	cr := &extensionsv1alpha1.ContainerRuntime{}
+	if err := h.sourceClient.Get(ctx, request, cr); err != nil {
+		return nil, fmt.Errorf("failed to read ContainerRuntime %q: %w", request, err)
+	}
+
+	// Probes can't be scheduled to worker pools without nodes, e.g. if the worker pool has been scaled down.
+	nodeList := &corev1.NodeList{}
+	if err := h.targetClient.List(ctx, nodeList, client.MatchingLabels{v1beta1constants.LabelWorkerPool: cr.Spec.WorkerPool.Name}); err != nil {
+		err := fmt.Errorf("failed to list nodes of worker pool %q: %w", cr.Spec.WorkerPool.Name, err)
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+	if len(nodeList.Items) == 0 {
+		return &healthcheck.SingleCheckResult{
+			Status: gardencorev1beta1.ConditionTrue,
+		}, nil
+	}
+
+	cronJob := &batchv1.CronJob{}
+	key := client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: CanaryCronJobName(cr)}
+	if err := h.targetClient.Get(ctx, key, cronJob); err != nil {
+		// The CronJob of a new worker pool might not have been created by the ManagedResource yet.
+		if apierrors.IsNotFound(err) {
+			return &healthcheck.SingleCheckResult{
+				Status:               gardencorev1beta1.ConditionProgressing,
+				Detail:               fmt.Sprintf("CronJob %q in namespace %q not found", key.Name, key.Namespace),
+				ProgressingThreshold: ptr.To(CanaryProgressingThreshold),
+			}, nil
+		}
+
+		err := fmt.Errorf("failed to retrieve CronJob %q in namespace %q: %w", key.Name, key.Namespace, err)
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+
+	job, err := h.latestFinishedJob(ctx, cronJob)
+	if err != nil {
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+
+	if job == nil {
+		return &healthcheck.SingleCheckResult{
+			Status:               gardencorev1beta1.ConditionProgressing,
+			Detail:               fmt.Sprintf("gVisor canary probe of worker pool %q has not completed yet", cr.Spec.WorkerPool.Name),
+			ProgressingThreshold: ptr.To(CanaryProgressingThreshold),
+		}, nil
+	}
+
+	if failed := jobCondition(job, batchv1.JobFailed); failed != nil {
+		return &healthcheck.SingleCheckResult{
+			Status: gardencorev1beta1.ConditionFalse,
+			Detail: fmt.Sprintf("gVisor canary probe %q of worker pool %q failed to start a sandbox: %s: %s", job.Name, cr.Spec.WorkerPool.Name, failed.Reason, failed.Message),
+		}, nil
+	}
+
+	if job.Status.StartTime != nil && job.Status.CompletionTime != nil {
+		latency := job.Status.CompletionTime.Sub(job.Status.StartTime.Time)
+		CanaryLatency.WithLabelValues(cr.Namespace, cr.Spec.WorkerPool.Name).Set(latency.Seconds())
+		h.logger.V(1).Info("gVisor canary probe succeeded", "job", client.ObjectKeyFromObject(job), "workerPoolName", cr.Spec.WorkerPool.Name, "latency", latency)
+	}
+
+	return &healthcheck.SingleCheckResult{
+		Status: gardencorev1beta1.ConditionTrue,
+	}, nil
+}
+
+// latestFinishedJob returns the latest finished Job of the given canary CronJob, or nil if no Job has finished yet.
+func (h *CanaryHealthChecker) latestFinishedJob(ctx context.Context, cronJob *batchv1.CronJob) (*batchv1.Job, error) {
+	jobList := &batchv1.JobList{}
+	if err := h.targetClient.List(ctx, jobList, client.InNamespace(cronJob.Namespace), client.MatchingLabels{"app.kubernetes.io/name": "gvisor-canary"}); err != nil {
+		return nil, fmt.Errorf("failed to list Jobs of CronJob %q in namespace %q: %w", cronJob.Name, cronJob.Namespace, err)
+	}
+
+	var latest *batchv1.Job
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if !metav1.IsControlledBy(job, cronJob) {
+			continue
+		}
+		if jobCondition(job, batchv1.JobComplete) == nil && jobCondition(job, batchv1.JobFailed) == nil {
+			continue
+		}
+		if latest == nil || latest.CreationTimestamp.Before(&job.CreationTimestamp) {
+			latest = job
+		}
+	}
+	return latest, nil
+}
+
+func jobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		if job.Status.Conditions[i].Type == conditionType && job.Status.Conditions[i].Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// CanaryCronJobName returns the name of the CronJob probing gVisor on the nodes of the worker pool of the given
+// ContainerRuntime.
+func CanaryCronJobName(cr *extensionsv1alpha1.ContainerRuntime) string {
+	return fmt.Sprintf("gvisor-canary-%s", cr.Spec.WorkerPool.Name)
+}
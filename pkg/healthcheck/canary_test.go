@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package healthcheck_test
+
+import (
+	"context"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/gardener/gardener-extension-runtime-gvisor/pkg/healthcheck"
+)
+
+var _ = Describe("CanaryHealthChecker", func() {
+	var (
+		ctx = context.Background()
+
+		shootClient client.Client
+		checker     *CanaryHealthChecker
+		request     types.NamespacedName
+		cronJob     *batchv1.CronJob
+		now         = time.Now()
+	)
+
+	createJob := func(name string, created time.Time, conditionType batchv1.JobConditionType) {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "kube-system",
+				Labels:            map[string]string{"app.kubernetes.io/name": "gvisor-canary"},
+				CreationTimestamp: metav1.NewTime(created),
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+				},
+			},
+			Status: batchv1.JobStatus{
+				StartTime:      ptr.To(metav1.NewTime(created)),
+				CompletionTime: ptr.To(metav1.NewTime(created.Add(3 * time.Second))),
+			},
+		}
+		if conditionType != "" {
+			job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue, Reason: "DeadlineExceeded", Message: "Job was active longer than specified deadline"}}
+		}
+		ExpectWithOffset(1, shootClient.Create(ctx, job)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		checker = NewCanaryHealthChecker()
+		shootClient, request = setUpShootHealthChecker(ctx, checker)
+		createNode(ctx, shootClient, "node-a", "worker", nil, nil)
+
+		cronJob = &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "gvisor-canary-worker", Namespace: "kube-system", UID: "canary-worker"}}
+		Expect(shootClient.Create(ctx, cronJob)).To(Succeed())
+	})
+
+	It("should be healthy if the latest probe succeeded", func() {
+		createJob("probe-1", now.Add(-10*time.Minute), batchv1.JobFailed)
+		createJob("probe-2", now.Add(-5*time.Minute), batchv1.JobComplete)
+		createJob("probe-3", now, "")
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionTrue))
+	})
+
+	It("should record the latency of the latest successful probe", func() {
+		createJob("probe-1", now.Add(-5*time.Minute), batchv1.JobComplete)
+
+		_, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(CanaryLatency.WithLabelValues("shoot--foo--bar", "worker"))).To(Equal(3.0))
+	})
+
+	It("should be unhealthy if the latest probe failed", func() {
+		createJob("probe-1", now.Add(-10*time.Minute), batchv1.JobComplete)
+		createJob("probe-2", now.Add(-5*time.Minute), batchv1.JobFailed)
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionFalse))
+		Expect(result.Detail).To(Equal(`gVisor canary probe "probe-2" of worker pool "worker" failed to start a sandbox: DeadlineExceeded: Job was active longer than specified deadline`))
+	})
+
+	It("should ignore the probes of other worker pools", func() {
+		createJob("probe-1", now.Add(-10*time.Minute), batchv1.JobComplete)
+
+		other := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "gvisor-canary-other", Namespace: "kube-system", UID: "canary-other"}}
+		Expect(shootClient.Create(ctx, other)).To(Succeed())
+		cronJob = other
+		createJob("other-probe", now, batchv1.JobFailed)
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionTrue))
+	})
+
+	It("should be progressing if no probe has completed yet", func() {
+		createJob("probe-1", now, "")
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionProgressing))
+		Expect(result.Detail).To(Equal(`gVisor canary probe of worker pool "worker" has not completed yet`))
+		Expect(result.ProgressingThreshold).To(HaveValue(Equal(CanaryProgressingThreshold)))
+	})
+
+	It("should be progressing if the CronJob has not run yet", func() {
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionProgressing))
+		Expect(result.Detail).To(Equal(`gVisor canary probe of worker pool "worker" has not completed yet`))
+		Expect(result.ProgressingThreshold).To(HaveValue(Equal(CanaryProgressingThreshold)))
+	})
+
+	It("should be progressing if the CronJob does not exist yet", func() {
+		Expect(shootClient.Delete(ctx, cronJob)).To(Succeed())
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionProgressing))
+		Expect(result.Detail).To(Equal(`CronJob "gvisor-canary-worker" in namespace "kube-system" not found`))
+		Expect(result.ProgressingThreshold).To(HaveValue(Equal(CanaryProgressingThreshold)))
+	})
+
+	It("should be healthy if the worker pool has no nodes", func() {
+		Expect(shootClient.Delete(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}})).To(Succeed())
+		Expect(shootClient.Delete(ctx, cronJob)).To(Succeed())
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionTrue))
+	})
+})
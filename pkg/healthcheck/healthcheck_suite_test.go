@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package healthcheck_test
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHealthCheck(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gvisor HealthCheck Test Suite")
+}
+
+// shootHealthChecker is a health checker which reads the ContainerRuntime from the seed and checks the shoot.
+type shootHealthChecker interface {
+	InjectSourceClient(client.Client)
+	InjectTargetClient(client.Client)
+	SetLoggerSuffix(provider, extension string)
+}
+
+// setUpShootHealthChecker injects fake seed and shoot clients into the given checker and creates the gVisor
+// ContainerRuntime of the worker pool `worker` in the seed. It returns the shoot client and the request for the
+// ContainerRuntime.
+func setUpShootHealthChecker(ctx context.Context, checker shootHealthChecker) (client.Client, types.NamespacedName) {
+	seedClient := fakeclient.NewClientBuilder().WithScheme(kubernetes.SeedScheme).Build()
+	shootClient := fakeclient.NewClientBuilder().WithScheme(kubernetes.ShootScheme).Build()
+
+	cr := &extensionsv1alpha1.ContainerRuntime{
+		ObjectMeta: metav1.ObjectMeta{Name: "gvisor-worker", Namespace: "shoot--foo--bar"},
+		Spec: extensionsv1alpha1.ContainerRuntimeSpec{
+			BinaryPath:  "/var/bin/containerruntimes",
+			DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: "gvisor"},
+			WorkerPool:  extensionsv1alpha1.ContainerRuntimeWorkerPool{Name: "worker"},
+		},
+	}
+	ExpectWithOffset(1, seedClient.Create(ctx, cr)).To(Succeed())
+
+	checker.InjectSourceClient(seedClient)
+	checker.InjectTargetClient(shootClient)
+	checker.SetLoggerSuffix("gvisor", "ContainerRuntime")
+
+	return shootClient, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+}
+
+// createNode creates a node of the given worker pool with the given labels and annotations in the shoot.
+func createNode(ctx context.Context, shootClient client.Client, name, pool string, labels, annotations map[string]string) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        name,
+		Labels:      map[string]string{"worker.gardener.cloud/pool": pool},
+		Annotations: annotations,
+	}}
+	maps.Copy(node.Labels, labels)
+	ExpectWithOffset(1, shootClient.Create(ctx, node)).To(Succeed())
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gardener/gardener/extensions/pkg/controller/healthcheck"
+	"github.com/gardener/gardener/extensions/pkg/controller/healthcheck/general"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils/kubernetes/health"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/controller"
+)
+
+// InstallationProgressingThreshold is the duration after which an installation which does not make progress, e.g. a
+// stuck rollout of the installation DaemonSet, is reported as unhealthy.
+const InstallationProgressingThreshold = 10 * time.Minute
+
+// InstallationManagedResourceHealthChecker checks the ManagedResource installing gVisor on the nodes of the worker pool
+// of a ContainerRuntime.
+type InstallationManagedResourceHealthChecker struct {
+	sourceClient client.Client
+	provider     string
+	extension    string
+}
+
+var (
+	_ healthcheck.HealthCheck  = (*InstallationManagedResourceHealthChecker)(nil)
+	_ healthcheck.SourceClient = (*InstallationManagedResourceHealthChecker)(nil)
+)
+
+// NewInstallationManagedResourceHealthChecker creates a new InstallationManagedResourceHealthChecker.
+func NewInstallationManagedResourceHealthChecker() *InstallationManagedResourceHealthChecker {
+	return &InstallationManagedResourceHealthChecker{}
+}
+
+// InjectSourceClient injects the seed client.
+func (h *InstallationManagedResourceHealthChecker) InjectSourceClient(sourceClient client.Client) {
+	h.sourceClient = sourceClient
+}
+
+// SetLoggerSuffix injects the logger.
+func (h *InstallationManagedResourceHealthChecker) SetLoggerSuffix(provider, extension string) {
+	h.provider, h.extension = provider, extension
+}
+
+// Check executes the health check.
+func (h *InstallationManagedResourceHealthChecker) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	cr := &extensionsv1alpha1.ContainerRuntime{}
+	if err := h.sourceClient.Get(ctx, request, cr); err != nil {
+		return nil, fmt.Errorf("failed to read ContainerRuntime %q: %w", request, err)
+	}
+
+	checker := general.CheckManagedResource(InstallationManagedResourceName(cr))
+	checker.InjectSourceClient(h.sourceClient)
+	checker.SetLoggerSuffix(h.provider, h.extension)
+	return checker.Check(ctx, request)
+}
+
+// InstallationDaemonSetHealthChecker checks the rollout of the DaemonSet installing gVisor on the nodes of the worker
+// pool of a ContainerRuntime.
+type InstallationDaemonSetHealthChecker struct {
+	logger       logr.Logger
+	sourceClient client.Client
+	targetClient client.Client
+}
+
+var (
+	_ healthcheck.HealthCheck  = (*InstallationDaemonSetHealthChecker)(nil)
+	_ healthcheck.SourceClient = (*InstallationDaemonSetHealthChecker)(nil)
+	_ healthcheck.TargetClient = (*InstallationDaemonSetHealthChecker)(nil)
+)
+
+// NewInstallationDaemonSetHealthChecker creates a new InstallationDaemonSetHealthChecker.
+func NewInstallationDaemonSetHealthChecker() *InstallationDaemonSetHealthChecker {
+	return &InstallationDaemonSetHealthChecker{}
+}
+
+// InjectSourceClient injects the seed client.
+func (h *InstallationDaemonSetHealthChecker) InjectSourceClient(sourceClient client.Client) {
+	h.sourceClient = sourceClient
+}
+
+// InjectTargetClient injects the shoot client.
+func (h *InstallationDaemonSetHealthChecker) InjectTargetClient(targetClient client.Client) {
+	h.targetClient = targetClient
+}
+
+// SetLoggerSuffix injects the logger.
+func (h *InstallationDaemonSetHealthChecker) SetLoggerSuffix(provider, extension string) {
+	h.logger = log.Log.WithName("healthcheck-gvisor-installation-daemonset").WithValues("provider", provider, "extension", extension)
+}
+
+// Check executes the health check.
+func (h *InstallationDaemonSetHealthChecker) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	cr := &extensionsv1alpha1.ContainerRuntime{}
+	if err := h.sourceClient.Get(ctx, request, cr); err != nil {
+		return nil, fmt.Errorf("failed to read ContainerRuntime %q: %w", request, err)
+	}
+
+	daemonSet := &appsv1.DaemonSet{}
+	key := client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: InstallationDaemonSetName(cr)}
+	if err := h.targetClient.Get(ctx, key, daemonSet); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &healthcheck.SingleCheckResult{
+				Status: gardencorev1beta1.ConditionFalse,
+				Detail: fmt.Sprintf("DaemonSet %q in namespace %q not found", key.Name, key.Namespace),
+			}, nil
+		}
+
+		err := fmt.Errorf("failed to retrieve DaemonSet %q in namespace %q: %w", key.Name, key.Namespace, err)
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+
+	if err := health.CheckDaemonSet(daemonSet); err != nil {
+		return &healthcheck.SingleCheckResult{
+			Status: gardencorev1beta1.ConditionFalse,
+			Detail: fmt.Sprintf("gVisor installation DaemonSet %q is unhealthy: %v", key.Name, err),
+		}, nil
+	}
+
+	// The rollout tolerates unavailable pods, hence a rollout which doesn't make progress (e.g. because the installer
+	// is crash-looping on a node) is only reported as progressing until the threshold is reached.
+	if progressing, reason := health.IsDaemonSetProgressing(daemonSet); progressing {
+		return &healthcheck.SingleCheckResult{
+			Status:               gardencorev1beta1.ConditionProgressing,
+			Detail:               fmt.Sprintf("gVisor installation DaemonSet %q is being rolled out: %s", key.Name, reason),
+			ProgressingThreshold: ptr.To(InstallationProgressingThreshold),
+		}, nil
+	}
+
+	return &healthcheck.SingleCheckResult{
+		Status: gardencorev1beta1.ConditionTrue,
+	}, nil
+}
+
+// InstallationManagedResourceName returns the name of the ManagedResource installing gVisor for the worker pool of the
+// given ContainerRuntime.
+func InstallationManagedResourceName(cr *extensionsv1alpha1.ContainerRuntime) string {
+	return fmt.Sprintf("%s-%s", controller.GVisorInstallationManagedResourceName, cr.Spec.WorkerPool.Name)
+}
+
+// InstallationDaemonSetName returns the name of the DaemonSet installing gVisor on the nodes of the worker pool of the
+// given ContainerRuntime.
+func InstallationDaemonSetName(cr *extensionsv1alpha1.ContainerRuntime) string {
+	return fmt.Sprintf("containerd-gvisor-%s", cr.Spec.WorkerPool.Name)
+}
@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package healthcheck_test
+
+import (
+	"context"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/gardener/gardener-extension-runtime-gvisor/pkg/healthcheck"
+)
+
+var _ = Describe("Installation health checks", func() {
+	const namespace = "shoot--foo--bar"
+
+	var (
+		ctx = context.Background()
+
+		seedClient  client.Client
+		shootClient client.Client
+
+		cr      *extensionsv1alpha1.ContainerRuntime
+		request types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		seedClient = fakeclient.NewClientBuilder().WithScheme(kubernetes.SeedScheme).Build()
+		shootClient = fakeclient.NewClientBuilder().WithScheme(kubernetes.ShootScheme).Build()
+
+		cr = &extensionsv1alpha1.ContainerRuntime{
+			ObjectMeta: metav1.ObjectMeta{Name: "gvisor-worker", Namespace: namespace},
+			Spec: extensionsv1alpha1.ContainerRuntimeSpec{
+				BinaryPath: "/var/bin/containerruntimes",
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{
+					Type: "gvisor",
+				},
+				WorkerPool: extensionsv1alpha1.ContainerRuntimeWorkerPool{
+					Name: "worker",
+				},
+			},
+		}
+		Expect(seedClient.Create(ctx, cr)).To(Succeed())
+		request = types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	})
+
+	Describe("InstallationManagedResourceHealthChecker", func() {
+		var checker *InstallationManagedResourceHealthChecker
+
+		BeforeEach(func() {
+			checker = NewInstallationManagedResourceHealthChecker()
+			checker.InjectSourceClient(seedClient)
+			checker.SetLoggerSuffix("gvisor", "ContainerRuntime")
+		})
+
+		createManagedResource := func(applied, healthy gardencorev1beta1.ConditionStatus) {
+			ExpectWithOffset(1, seedClient.Create(ctx, &resourcesv1alpha1.ManagedResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "extension-runtime-gvisor-installation-worker", Namespace: namespace, Generation: 1},
+				Status: resourcesv1alpha1.ManagedResourceStatus{
+					ObservedGeneration: 1,
+					Conditions: []gardencorev1beta1.Condition{
+						{Type: resourcesv1alpha1.ResourcesApplied, Status: applied},
+						{Type: resourcesv1alpha1.ResourcesHealthy, Status: healthy},
+					},
+				},
+			})).To(Succeed())
+		}
+
+		It("should check the installation ManagedResource of the worker pool", func() {
+			createManagedResource(gardencorev1beta1.ConditionTrue, gardencorev1beta1.ConditionTrue)
+
+			result, err := checker.Check(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Status).To(Equal(gardencorev1beta1.ConditionTrue))
+		})
+
+		It("should report an unhealthy installation ManagedResource", func() {
+			createManagedResource(gardencorev1beta1.ConditionTrue, gardencorev1beta1.ConditionFalse)
+
+			result, err := checker.Check(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Status).To(Equal(gardencorev1beta1.ConditionFalse))
+		})
+
+		It("should report a missing installation ManagedResource", func() {
+			result, err := checker.Check(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Status).To(Equal(gardencorev1beta1.ConditionFalse))
+			Expect(result.Detail).To(ContainSubstring("extension-runtime-gvisor-installation-worker"))
+		})
+	})
+
+	Describe("InstallationDaemonSetHealthChecker", func() {
+		var (
+			checker   *InstallationDaemonSetHealthChecker
+			daemonSet *appsv1.DaemonSet
+		)
+
+		BeforeEach(func() {
+			checker = NewInstallationDaemonSetHealthChecker()
+			checker.InjectSourceClient(seedClient)
+			checker.InjectTargetClient(shootClient)
+			checker.SetLoggerSuffix("gvisor", "ContainerRuntime")
+
+			daemonSet = &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "containerd-gvisor-worker", Namespace: metav1.NamespaceSystem, Generation: 1},
+				Spec: appsv1.DaemonSetSpec{
+					UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+						Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+						RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: ptr.To(intstr.FromInt32(1))},
+					},
+				},
+				Status: appsv1.DaemonSetStatus{
+					ObservedGeneration:     1,
+					DesiredNumberScheduled: 3,
+					CurrentNumberScheduled: 3,
+					UpdatedNumberScheduled: 3,
+					NumberAvailable:        3,
+					NumberReady:            3,
+				},
+			}
+		})
+
+		It("should report a rolled out DaemonSet as healthy", func() {
+			Expect(shootClient.Create(ctx, daemonSet)).To(Succeed())
+
+			result, err := checker.Check(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Status).To(Equal(gardencorev1beta1.ConditionTrue))
+		})
+
+		It("should report a crash-looping installer", func() {
+			daemonSet.Status.NumberAvailable = 2
+			daemonSet.Status.NumberUnavailable = 1
+			Expect(shootClient.Create(ctx, daemonSet)).To(Succeed())
+
+			result, err := checker.Check(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Status).To(Equal(gardencorev1beta1.ConditionFalse))
+			Expect(result.Detail).To(ContainSubstring("too many unavailable pods found (1/3)"))
+		})
+
+		It("should report an ongoing rollout as progressing", func() {
+			daemonSet.Status.UpdatedNumberScheduled = 2
+			daemonSet.Status.NumberAvailable = 2
+			daemonSet.Status.NumberUnavailable = 1
+			Expect(shootClient.Create(ctx, daemonSet)).To(Succeed())
+
+			result, err := checker.Check(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Status).To(Equal(gardencorev1beta1.ConditionProgressing))
+			Expect(result.ProgressingThreshold).To(HaveValue(Equal(InstallationProgressingThreshold)))
+		})
+
+		It("should report a missing DaemonSet", func() {
+			result, err := checker.Check(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Status).To(Equal(gardencorev1beta1.ConditionFalse))
+			Expect(result.Detail).To(ContainSubstring("not found"))
+		})
+	})
+})
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gardener/gardener/extensions/pkg/controller/healthcheck"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+)
+
+// NodesHealthChecker checks that gVisor has been installed on all nodes of the worker pool of a ContainerRuntime.
+// The node installer labels a node as ready once the installation has been verified and annotates it with the error
+// if the installation failed.
+type NodesHealthChecker struct {
+	logger       logr.Logger
+	sourceClient client.Client
+	targetClient client.Client
+}
+
+var (
+	_ healthcheck.HealthCheck  = (*NodesHealthChecker)(nil)
+	_ healthcheck.SourceClient = (*NodesHealthChecker)(nil)
+	_ healthcheck.TargetClient = (*NodesHealthChecker)(nil)
+)
+
+// NewNodesHealthChecker creates a new NodesHealthChecker.
+func NewNodesHealthChecker() *NodesHealthChecker {
+	return &NodesHealthChecker{}
+}
+
+// InjectSourceClient injects the seed client.
+func (h *NodesHealthChecker) InjectSourceClient(sourceClient client.Client) {
+	h.sourceClient = sourceClient
+}
+
+// InjectTargetClient injects the shoot client.
+func (h *NodesHealthChecker) InjectTargetClient(targetClient client.Client) {
+	h.targetClient = targetClient
+}
+
+// SetLoggerSuffix injects the logger.
+func (h *NodesHealthChecker) SetLoggerSuffix(provider, extension string) {
+	h.logger = log.Log.WithName("healthcheck-gvisor-nodes").WithValues("provider", provider, "extension", extension)
+}
+
+// Check executes the health check.
+func (h *NodesHealthChecker) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	cr := &extensionsv1alpha1.ContainerRuntime{}
+	if err := h.sourceClient.Get(ctx, request, cr); err != nil {
+		return nil, fmt.Errorf("failed to read ContainerRuntime %q: %w", request, err)
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := h.targetClient.List(ctx, nodeList, client.MatchingLabels{v1beta1constants.LabelWorkerPool: cr.Spec.WorkerPool.Name}); err != nil {
+		err := fmt.Errorf("failed to list nodes of worker pool %q: %w", cr.Spec.WorkerPool.Name, err)
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+
+	var notReady, failed, tampered []string
+	for _, node := range nodeList.Items {
+		if paths, ok := node.Annotations[gvisor.AnnotationTamperedBinaries]; ok {
+			tampered = append(tampered, fmt.Sprintf("%s: %s", node.Name, paths))
+		}
+		if installationError, ok := node.Annotations[gvisor.AnnotationInstallationError]; ok {
+			failed = append(failed, fmt.Sprintf("%s: %s", node.Name, installationError))
+		} else if node.Labels[gvisor.LabelReady] != "true" {
+			notReady = append(notReady, node.Name)
+		}
+	}
+
+	var details []string
+	// Modified binaries have been reinstalled by the installer, but the nodes are reported until the annotation has
+	// been removed by an operator, as their integrity is in doubt.
+	if len(tampered) > 0 {
+		slices.Sort(tampered)
+		details = append(details, fmt.Sprintf("installed gVisor binaries have been modified on %d/%d nodes of worker pool %q: %s",
+			len(tampered), len(nodeList.Items), cr.Spec.WorkerPool.Name, strings.Join(tampered, "; ")))
+	}
+	// The installer marks nodes as failed if it could not install or verify gVisor, e.g. if the binaries of a new
+	// release have been rolled back.
+	if len(failed) > 0 {
+		slices.Sort(failed)
+		details = append(details, fmt.Sprintf("gVisor installation failed on %d/%d nodes of worker pool %q: %s",
+			len(failed), len(nodeList.Items), cr.Spec.WorkerPool.Name, strings.Join(failed, "; ")))
+	}
+	if len(details) > 0 {
+		return &healthcheck.SingleCheckResult{
+			Status: gardencorev1beta1.ConditionFalse,
+			Detail: strings.Join(details, ", "),
+		}, nil
+	}
+
+	if len(notReady) > 0 {
+		slices.Sort(notReady)
+		// New nodes are not ready until the installer has been scheduled to them, hence they are only reported as
+		// progressing until the threshold is reached.
+		return &healthcheck.SingleCheckResult{
+			Status: gardencorev1beta1.ConditionProgressing,
+			Detail: fmt.Sprintf("gVisor is installed on %d/%d nodes of worker pool %q, not ready: %s",
+				len(nodeList.Items)-len(notReady), len(nodeList.Items), cr.Spec.WorkerPool.Name, strings.Join(notReady, ", ")),
+			ProgressingThreshold: ptr.To(InstallationProgressingThreshold),
+		}, nil
+	}
+
+	return &healthcheck.SingleCheckResult{
+		Status: gardencorev1beta1.ConditionTrue,
+	}, nil
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package healthcheck_test
+
+import (
+	"context"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/gardener/gardener-extension-runtime-gvisor/pkg/healthcheck"
+)
+
+var _ = Describe("NodesHealthChecker", func() {
+	var (
+		ctx = context.Background()
+
+		shootClient client.Client
+		checker     *NodesHealthChecker
+		request     types.NamespacedName
+	)
+
+	installed := map[string]string{"gvisor.runtime.extensions.gardener.cloud/ready": "true"}
+
+	BeforeEach(func() {
+		checker = NewNodesHealthChecker()
+		shootClient, request = setUpShootHealthChecker(ctx, checker)
+	})
+
+	It("should be healthy if gVisor is installed on all nodes of the worker pool", func() {
+		createNode(ctx, shootClient, "node-a", "worker", installed, nil)
+		createNode(ctx, shootClient, "node-b", "worker", installed, nil)
+		createNode(ctx, shootClient, "node-c", "other", nil, nil)
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionTrue))
+	})
+
+	It("should report the nodes on which gVisor is not installed yet", func() {
+		createNode(ctx, shootClient, "node-a", "worker", installed, nil)
+		createNode(ctx, shootClient, "node-c", "worker", nil, nil)
+		createNode(ctx, shootClient, "node-b", "worker", map[string]string{"gvisor.runtime.extensions.gardener.cloud/ready": "false"}, nil)
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionProgressing))
+		Expect(result.Detail).To(Equal(`gVisor is installed on 1/3 nodes of worker pool "worker", not ready: node-b, node-c`))
+		Expect(result.ProgressingThreshold).To(HaveValue(Equal(InstallationProgressingThreshold)))
+	})
+
+	It("should report the nodes on which the installation failed", func() {
+		createNode(ctx, shootClient, "node-a", "worker", installed, nil)
+		createNode(ctx, shootClient, "node-b", "worker", nil, nil)
+		createNode(ctx, shootClient, "node-c", "worker", nil, map[string]string{"gvisor.runtime.extensions.gardener.cloud/installation-error": "failed determining runsc version: exec format error, rolled back to the previous binaries"})
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionFalse))
+		Expect(result.Detail).To(Equal(`gVisor installation failed on 1/3 nodes of worker pool "worker": node-c: failed determining runsc version: exec format error, rolled back to the previous binaries`))
+	})
+
+	It("should report the nodes on which installed binaries have been modified", func() {
+		createNode(ctx, shootClient, "node-a", "worker", installed, nil)
+		createNode(ctx, shootClient, "node-b", "worker", installed, map[string]string{"gvisor.runtime.extensions.gardener.cloud/tampered-binaries": "/var/bin/containerruntimes/runsc"})
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionFalse))
+		Expect(result.Detail).To(Equal(`installed gVisor binaries have been modified on 1/2 nodes of worker pool "worker": node-b: /var/bin/containerruntimes/runsc`))
+	})
+})
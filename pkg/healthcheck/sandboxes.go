@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gardener/gardener/extensions/pkg/controller/healthcheck"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+)
+
+// ConditionTypeSandboxesUpToDate is the type of the condition on the ContainerRuntime which reports whether all running
+// sandboxes on the nodes of the worker pool use the installed gVisor binaries.
+const ConditionTypeSandboxesUpToDate = "SandboxesUpToDate"
+
+// OutdatedSandboxesHealthChecker checks that no running sandboxes on the nodes of the worker pool of a ContainerRuntime
+// use replaced gVisor binaries. The node installer annotates each node with the number of such sandboxes, which only
+// use the installed binaries once their pods are restarted.
+type OutdatedSandboxesHealthChecker struct {
+	logger       logr.Logger
+	sourceClient client.Client
+	targetClient client.Client
+}
+
+var (
+	_ healthcheck.HealthCheck  = (*OutdatedSandboxesHealthChecker)(nil)
+	_ healthcheck.SourceClient = (*OutdatedSandboxesHealthChecker)(nil)
+	_ healthcheck.TargetClient = (*OutdatedSandboxesHealthChecker)(nil)
+)
+
+// NewOutdatedSandboxesHealthChecker creates a new OutdatedSandboxesHealthChecker.
+func NewOutdatedSandboxesHealthChecker() *OutdatedSandboxesHealthChecker {
+	return &OutdatedSandboxesHealthChecker{}
+}
+
+// InjectSourceClient injects the seed client.
+func (h *OutdatedSandboxesHealthChecker) InjectSourceClient(sourceClient client.Client) {
+	h.sourceClient = sourceClient
+}
+
+// InjectTargetClient injects the shoot client.
+func (h *OutdatedSandboxesHealthChecker) InjectTargetClient(targetClient client.Client) {
+	h.targetClient = targetClient
+}
+
+// SetLoggerSuffix injects the logger.
+func (h *OutdatedSandboxesHealthChecker) SetLoggerSuffix(provider, extension string) {
+	h.logger = log.Log.WithName("healthcheck-gvisor-outdated-sandboxes").WithValues("provider", provider, "extension", extension)
+}
+
+// Check executes the health check.
+func (h *OutdatedSandboxesHealthChecker) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	cr := &extensionsv1alpha1.ContainerRuntime{}
+	if err := h.sourceClient.Get(ctx, request, cr); err != nil {
+		return nil, fmt.Errorf("failed to read ContainerRuntime %q: %w", request, err)
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := h.targetClient.List(ctx, nodeList, client.MatchingLabels{v1beta1constants.LabelWorkerPool: cr.Spec.WorkerPool.Name}); err != nil {
+		err := fmt.Errorf("failed to list nodes of worker pool %q: %w", cr.Spec.WorkerPool.Name, err)
+		h.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+
+	var (
+		total    int
+		outdated []string
+	)
+	for _, node := range nodeList.Items {
+		// Nodes on which gVisor has not been installed yet are reported by the NodesHealthChecker.
+		count, err := strconv.Atoi(node.Annotations[gvisor.AnnotationOutdatedSandboxes])
+		if err != nil || count <= 0 {
+			continue
+		}
+		total += count
+		outdated = append(outdated, fmt.Sprintf("%s: %d", node.Name, count))
+	}
+
+	if len(outdated) > 0 {
+		slices.Sort(outdated)
+		return &healthcheck.SingleCheckResult{
+			Status: gardencorev1beta1.ConditionFalse,
+			Detail: fmt.Sprintf("%d running sandboxes on %d/%d nodes of worker pool %q use replaced gVisor binaries, their pods have to be restarted to use the installed ones: %s",
+				total, len(outdated), len(nodeList.Items), cr.Spec.WorkerPool.Name, strings.Join(outdated, ", ")),
+		}, nil
+	}
+
+	return &healthcheck.SingleCheckResult{
+		Status: gardencorev1beta1.ConditionTrue,
+	}, nil
+}
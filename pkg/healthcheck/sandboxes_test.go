@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package healthcheck_test
+
+import (
+	"context"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/gardener/gardener-extension-runtime-gvisor/pkg/healthcheck"
+)
+
+var _ = Describe("OutdatedSandboxesHealthChecker", func() {
+	var (
+		ctx = context.Background()
+
+		shootClient client.Client
+		checker     *OutdatedSandboxesHealthChecker
+		request     types.NamespacedName
+	)
+
+	outdatedSandboxes := func(count string) map[string]string {
+		return map[string]string{"gvisor.runtime.extensions.gardener.cloud/outdated-sandboxes": count}
+	}
+
+	BeforeEach(func() {
+		checker = NewOutdatedSandboxesHealthChecker()
+		shootClient, request = setUpShootHealthChecker(ctx, checker)
+	})
+
+	It("should be healthy if no sandboxes use replaced binaries", func() {
+		createNode(ctx, shootClient, "node-a", "worker", nil, outdatedSandboxes("0"))
+		createNode(ctx, shootClient, "node-b", "worker", nil, nil)
+		createNode(ctx, shootClient, "node-c", "other", nil, outdatedSandboxes("5"))
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionTrue))
+	})
+
+	It("should report the nodes with sandboxes which use replaced binaries", func() {
+		createNode(ctx, shootClient, "node-a", "worker", nil, outdatedSandboxes("0"))
+		createNode(ctx, shootClient, "node-c", "worker", nil, outdatedSandboxes("1"))
+		createNode(ctx, shootClient, "node-b", "worker", nil, outdatedSandboxes("3"))
+
+		result, err := checker.Check(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(gardencorev1beta1.ConditionFalse))
+		Expect(result.Detail).To(Equal(`4 running sandboxes on 2/3 nodes of worker pool "worker" use replaced gVisor binaries, their pods have to be restarted to use the installed ones: node-b: 3, node-c: 1`))
+	})
+})
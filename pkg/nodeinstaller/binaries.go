@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinstaller
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// previousSuffix is the suffix of the copy of a replaced binary, which is kept for rolling back.
+const previousSuffix = ".previous"
+
+// installBinary copies the binary at source to target if target does not exist or differs from source. An existing
+// binary at target is kept with the previousSuffix, see rollbackBinary. It returns true if the binary was installed
+// and whether a previous binary has been kept, also if writing the binary failed afterwards.
+func installBinary(source, target string) (bool, bool, error) {
+	content, err := os.ReadFile(source) // #nosec G304 -- source is located in the installation image.
+	if err != nil {
+		return false, false, err
+	}
+
+	existing, err := os.ReadFile(target) // #nosec G304 -- target is not user provided.
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, false, err
+	}
+	if err == nil && bytes.Equal(content, existing) {
+		return false, false, nil
+	}
+
+	hadPrevious := err == nil
+	if hadPrevious {
+		if err := writeFileAtomically(target+previousSuffix, existing, 0755); err != nil {
+			return false, false, fmt.Errorf("failed keeping previous binary: %w", err)
+		}
+	}
+	return true, hadPrevious, WriteBinary(target, content, 0755)
+}
+
+// rollbackBinary restores the previous binary of target, which has been kept by installBinary.
+func rollbackBinary(target string) error {
+	return os.Rename(target+previousSuffix, target)
+}
+
+// checksums are the expected sha512 checksums of the binaries by their name.
+type checksums map[string]string
+
+// readChecksums reads the checksum manifest at path, which has the output format of `sha512sum`, i.e. one
+// `<checksum>  <file name>` line per binary.
+func readChecksums(path string) (checksums, error) {
+	content, err := os.ReadFile(path) // #nosec G304 -- path is located in the installation image.
+	if err != nil {
+		return nil, err
+	}
+
+	result := checksums{}
+	for line := range strings.Lines(string(content)) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 || len(fields[0]) != 2*sha512.Size {
+			return nil, fmt.Errorf("invalid line in checksum manifest: %q", strings.TrimSpace(line))
+		}
+		// sha512sum marks files read in binary mode with a leading asterisk.
+		result[filepath.Base(strings.TrimPrefix(fields[1], "*"))] = strings.ToLower(fields[0])
+	}
+	return result, nil
+}
+
+// verify checks that the checksum of the binary at path matches the expected checksum of the binary with the given
+// name.
+func (c checksums) verify(name, path string) error {
+	expected, ok := c[name]
+	if !ok {
+		return fmt.Errorf("checksum manifest does not contain binary %s", name)
+	}
+
+	actual, err := fileChecksum(path)
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return fmt.Errorf("checksum of %s %s does not match the expected checksum %s", path, actual, expected)
+	}
+	return nil
+}
+
+func fileChecksum(path string) (string, error) {
+	content, err := os.ReadFile(path) // #nosec G304 -- path is not user provided.
+	if err != nil {
+		return "", err
+	}
+	checksum := sha512.Sum512(content)
+	return hex.EncodeToString(checksum[:]), nil
+}
+
+// writeFileIfChanged writes the content to the file at path if its content differs. It returns true if the file was
+// written.
+func writeFileIfChanged(path string, content []byte, perm os.FileMode) (bool, error) {
+	existing, err := os.ReadFile(path) // #nosec G304 -- path is not user provided.
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	if err == nil && bytes.Equal(content, existing) {
+		return false, nil
+	}
+
+	return true, writeFileAtomically(path, content, perm)
+}
+
+// writeFileAtomically writes the content into a temporary file next to path and renames it afterwards. Running
+// processes of a replaced binary keep using the old file.
+func writeFileAtomically(path string, content []byte, perm os.FileMode) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, perm); err != nil {
+		return err
+	}
+	// The permissions of an existing temporary file are not changed by os.WriteFile.
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
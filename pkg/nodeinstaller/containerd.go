@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinstaller
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/nodeinstaller/containerdconfig"
+)
+
+// RunscRuntimeName is the name of the runsc runtime in the containerd config.
+const RunscRuntimeName = "runsc"
+
+// RunscRuntime is the runsc runtime entry in the containerd config.
+var RunscRuntime = containerdconfig.Runtime{
+	Type: "io.containerd.runsc.v1",
+	Options: map[string]any{
+		"TypeUrl":    "io.containerd.runsc.v1.options",
+		"ConfigPath": RunscConfigPath,
+	},
+}
+
+// ProfileRuntimeName returns the name of the runtime of the profile with the given name in the containerd config. It
+// is the handler of the RuntimeClass of the profile.
+func ProfileRuntimeName(profile string) string {
+	return RunscRuntimeName + "-" + profile
+}
+
+// profileRuntime returns the runtime entry of the profile with the given name in the containerd config. It only differs
+// from the runsc runtime in the runsc config.
+func profileRuntime(profile string) containerdconfig.Runtime {
+	options := maps.Clone(RunscRuntime.Options)
+	options["ConfigPath"] = ProfileRunscConfigPath(profile)
+	return containerdconfig.Runtime{Type: RunscRuntime.Type, Options: options}
+}
+
+// runtimes returns the runsc runtime and the runtimes of the configured profiles by their name.
+func (i *Installer) runtimes() map[string]containerdconfig.Runtime {
+	runtimes := map[string]containerdconfig.Runtime{RunscRuntimeName: RunscRuntime}
+	for profile := range i.opts.Profiles {
+		runtimes[ProfileRuntimeName(profile)] = profileRuntime(profile)
+	}
+	return runtimes
+}
+
+// configureContainerd configures the runsc runtime and the runtimes of the profiles in containerd according to the
+// configured mode. The runtimes are removed from the location which is not used by the mode, e.g. if a node is switched
+// to the drop-in mode, as well as the runtimes of profiles which are no longer configured. It returns the paths of the
+// changed containerd config files.
+func (i *Installer) configureContainerd() ([]string, error) {
+	containerdConfig, err := i.readContainerdConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	useDropIn, err := i.useDropIn(containerdConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimes := i.runtimes()
+	var configChanged, dropInChanged bool
+	if useDropIn {
+		i.log.Info("Configuring runsc runtimes in containerd drop-in config", "path", ContainerdDropInPath)
+		if dropInChanged, err = i.writeDropIn(containerdConfig.Version(), runtimes); err != nil {
+			return nil, err
+		}
+		if configChanged, err = removeRuntimes(containerdConfig, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		i.log.Info("Configuring runsc runtimes in containerd config", "version", containerdConfig.Version(), "plugin", containerdConfig.CRIPluginName())
+		for _, name := range slices.Sorted(maps.Keys(runtimes)) {
+			changed, err := containerdConfig.SetRuntime(name, runtimes[name])
+			if err != nil {
+				return nil, err
+			}
+			configChanged = configChanged || changed
+		}
+		removed, err := removeRuntimes(containerdConfig, runtimes)
+		if err != nil {
+			return nil, err
+		}
+		configChanged = configChanged || removed
+		if dropInChanged, err = i.removeFile(ContainerdDropInPath); err != nil {
+			return nil, err
+		}
+	}
+
+	var changed []string
+	if configChanged {
+		if err := i.writeContainerdConfig(containerdConfig); err != nil {
+			return nil, err
+		}
+		changed = append(changed, ContainerdConfigPath)
+	}
+	if dropInChanged {
+		changed = append(changed, ContainerdDropInPath)
+	}
+
+	if len(changed) == 0 {
+		i.log.Info("Containerd is already configured for gVisor")
+	}
+	return changed, nil
+}
+
+// useDropIn returns whether the runtime is configured in a drop-in file.
+func (i *Installer) useDropIn(containerdConfig *containerdconfig.Config) (bool, error) {
+	imported := containerdConfig.Imports(ContainerdDropInPath, filepath.Dir(ContainerdConfigPath))
+
+	switch i.opts.ContainerdConfigMode {
+	case gvisor.ContainerdConfigModeDropIn:
+		if !imported {
+			return false, fmt.Errorf("containerd config %s does not import the drop-in config %s", ContainerdConfigPath, ContainerdDropInPath)
+		}
+		return true, nil
+	case gvisor.ContainerdConfigModeConfigFile:
+		return false, nil
+	default:
+		return imported, nil
+	}
+}
+
+func (i *Installer) readContainerdConfig() (*containerdconfig.Config, error) {
+	content, err := os.ReadFile(i.hostPath(ContainerdConfigPath)) // #nosec G304 -- path is not user provided.
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return containerdconfig.Parse(content)
+}
+
+func (i *Installer) writeContainerdConfig(containerdConfig *containerdconfig.Config) error {
+	content, err := containerdConfig.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(i.hostPath(ContainerdConfigPath), content, 0644)
+}
+
+// removeRuntimes removes the runsc runtime and the runtimes of profiles from the given containerd config, except for
+// the given runtimes. Runtimes of profiles are recognized by their name and type. It returns true if the config was
+// changed.
+func removeRuntimes(containerdConfig *containerdconfig.Config, keep map[string]containerdconfig.Runtime) (bool, error) {
+	changed := false
+	for _, name := range containerdConfig.RuntimeNames() {
+		if _, ok := keep[name]; ok {
+			continue
+		}
+		if name != RunscRuntimeName {
+			runtime, _ := containerdConfig.Runtime(name)
+			if !strings.HasPrefix(name, RunscRuntimeName+"-") || runtime.Type != RunscRuntime.Type {
+				continue
+			}
+		}
+
+		removed, err := containerdConfig.RemoveRuntime(name)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || removed
+	}
+	return changed, nil
+}
+
+// writeDropIn writes the drop-in config with the given runtimes. It uses the same version as the containerd config, so
+// that the runtimes are added to the same plugin. It returns true if the drop-in config was changed.
+func (i *Installer) writeDropIn(version int, runtimes map[string]containerdconfig.Runtime) (bool, error) {
+	dropIn := containerdconfig.New(version)
+	for name, runtime := range runtimes {
+		if _, err := dropIn.SetRuntime(name, runtime); err != nil {
+			return false, err
+		}
+	}
+
+	content, err := dropIn.Marshal()
+	if err != nil {
+		return false, err
+	}
+
+	path := i.hostPath(ContainerdDropInPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return writeFileIfChanged(path, content, 0644)
+}
+
+// removeFile removes the file at the given path on the node. It returns true if the file existed.
+func (i *Installer) removeFile(path string) (bool, error) {
+	if err := os.Remove(i.hostPath(path)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
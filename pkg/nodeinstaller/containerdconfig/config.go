@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package containerdconfig
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	keyVersion     = "version"
+	keyImports     = "imports"
+	keyPlugins     = "plugins"
+	keyContainerd  = "containerd"
+	keyRuntimes    = "runtimes"
+	keyRuntimeType = "runtime_type"
+	keyOptions     = "options"
+)
+
+// Runtime is a runtime entry of the CRI plugin of containerd.
+type Runtime struct {
+	// Type is the runtime type, e.g. `io.containerd.runsc.v1`.
+	Type string
+	// Options are the options of the runtime. They replace all existing options of the runtime.
+	Options map[string]any
+}
+
+// Config is a parsed containerd config. All content which is not related to the modified runtimes is preserved, only
+// comments and the formatting of the original file are lost when it is marshalled again.
+type Config struct {
+	tree    map[string]any
+	version int
+}
+
+// Parse parses the given containerd config. Config versions 1 to 4 are supported.
+func Parse(data []byte) (*Config, error) {
+	tree := map[string]any{}
+	if _, err := toml.NewDecoder(bytes.NewReader(data)).Decode(&tree); err != nil {
+		return nil, fmt.Errorf("failed parsing containerd config: %w", err)
+	}
+
+	// If no version is specified, version 1 is assumed, see https://containerd.io/releases/#daemon-configuration.
+	version := 1
+	if value, ok := tree[keyVersion]; ok {
+		v, ok := value.(int64)
+		if !ok {
+			return nil, fmt.Errorf("containerd config version must be an integer, got %v", value)
+		}
+		version = int(v)
+	}
+	if version < 1 || version > 4 {
+		return nil, fmt.Errorf("unsupported containerd config version %d", version)
+	}
+
+	return &Config{tree: tree, version: version}, nil
+}
+
+// New creates an empty config with the given version.
+func New(version int) *Config {
+	return &Config{
+		tree:    map[string]any{keyVersion: int64(version)},
+		version: version,
+	}
+}
+
+// Version returns the version of the config.
+func (c *Config) Version() int {
+	return c.version
+}
+
+// CRIPluginName returns the name of the plugin which contains the runtimes in the config. Depending on the version of
+// containerd, a different config version with different plugin names is used:
+//   - containerd 2.3 uses version 4 by default
+//   - containerd 2.1 uses version 3 by default
+//   - containerd 1.7 uses version 2 by default
+func (c *Config) CRIPluginName() string {
+	switch c.version {
+	case 1:
+		return "cri"
+	case 2:
+		return "io.containerd.grpc.v1.cri"
+	default:
+		return "io.containerd.cri.v1.runtime"
+	}
+}
+
+// Imports returns whether the file at the given absolute path is imported by the config. Relative imports are resolved
+// against the given directory of the config file, like containerd does.
+func (c *Config) Imports(path, configDir string) bool {
+	imports, ok := c.tree[keyImports].([]any)
+	if !ok {
+		return false
+	}
+
+	for _, value := range imports {
+		pattern, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(configDir, pattern)
+		}
+		// containerd expands the imports with filepath.Glob.
+		if matched, err := filepath.Match(pattern, path); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Runtime returns the runtime with the given name and whether it exists.
+func (c *Config) Runtime(name string) (Runtime, bool) {
+	runtimes, err := c.runtimes(false)
+	if err != nil || runtimes == nil {
+		return Runtime{}, false
+	}
+
+	table, ok := runtimes[name].(map[string]any)
+	if !ok {
+		return Runtime{}, false
+	}
+
+	runtime := Runtime{}
+	runtime.Type, _ = table[keyRuntimeType].(string)
+	runtime.Options, _ = table[keyOptions].(map[string]any)
+	return runtime, true
+}
+
+// RuntimeNames returns the sorted names of all runtimes in the config.
+func (c *Config) RuntimeNames() []string {
+	runtimes, err := c.runtimes(false)
+	if err != nil {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(runtimes))
+}
+
+// SetRuntime inserts the runtime with the given name or updates its type and options. Other keys of an existing
+// runtime are preserved. It returns true if the config was changed.
+func (c *Config) SetRuntime(name string, runtime Runtime) (bool, error) {
+	runtimes, err := c.runtimes(true)
+	if err != nil {
+		return false, err
+	}
+
+	table, ok := runtimes[name].(map[string]any)
+	if !ok {
+		if _, exists := runtimes[name]; exists {
+			return false, fmt.Errorf("runtime %q is not a table", name)
+		}
+		table = map[string]any{}
+		runtimes[name] = table
+	}
+
+	changed := !ok
+	if table[keyRuntimeType] != runtime.Type {
+		table[keyRuntimeType] = runtime.Type
+		changed = true
+	}
+
+	options := maps.Clone(runtime.Options)
+	if options == nil {
+		options = map[string]any{}
+	}
+	if !reflect.DeepEqual(table[keyOptions], options) {
+		table[keyOptions] = options
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// RemoveRuntime removes the runtime with the given name. It returns true if the config was changed.
+func (c *Config) RemoveRuntime(name string) (bool, error) {
+	runtimes, err := c.runtimes(false)
+	if err != nil || runtimes == nil {
+		return false, err
+	}
+
+	if _, ok := runtimes[name]; !ok {
+		return false, nil
+	}
+
+	delete(runtimes, name)
+	return true, nil
+}
+
+// Marshal returns the config in TOML format.
+func (c *Config) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(c.tree); err != nil {
+		return nil, fmt.Errorf("failed encoding containerd config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// runtimes returns the runtimes table of the CRI plugin. If create is true, missing tables are created, otherwise nil
+// is returned if a table is missing.
+func (c *Config) runtimes(create bool) (map[string]any, error) {
+	table := c.tree
+	for _, key := range []string{keyPlugins, c.CRIPluginName(), keyContainerd, keyRuntimes} {
+		value, ok := table[key]
+		if !ok {
+			if !create {
+				return nil, nil
+			}
+			value = map[string]any{}
+			table[key] = value
+		}
+
+		subTable, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("key %q of containerd config is not a table", key)
+		}
+		table = subTable
+	}
+	return table, nil
+}
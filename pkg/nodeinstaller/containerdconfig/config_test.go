@@ -0,0 +1,207 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package containerdconfig_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gardener/gardener-extension-runtime-gvisor/pkg/nodeinstaller/containerdconfig"
+)
+
+var _ = Describe("Config", func() {
+	var runtime = Runtime{
+		Type: "io.containerd.runsc.v1",
+		Options: map[string]any{
+			"TypeUrl":    "io.containerd.runsc.v1.options",
+			"ConfigPath": "/etc/containerd/runsc.toml",
+		},
+	}
+
+	parse := func(data string) *Config {
+		config, err := Parse([]byte(data))
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return config
+	}
+
+	marshal := func(config *Config) string {
+		data, err := config.Marshal()
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return string(data)
+	}
+
+	Describe("#Parse", func() {
+		DescribeTable("should detect the version and CRI plugin",
+			func(data string, expectedVersion int, expectedPlugin string) {
+				config := parse(data)
+				Expect(config.Version()).To(Equal(expectedVersion))
+				Expect(config.CRIPluginName()).To(Equal(expectedPlugin))
+			},
+			Entry("empty config", "", 1, "cri"),
+			Entry("no version", "[plugins.cri]\n", 1, "cri"),
+			Entry("version 1", "version = 1\n", 1, "cri"),
+			Entry("version 2", "version = 2\n", 2, "io.containerd.grpc.v1.cri"),
+			Entry("version 3", "version=3\n", 3, "io.containerd.cri.v1.runtime"),
+			Entry("version 4", "version = 4\n", 4, "io.containerd.cri.v1.runtime"),
+		)
+
+		DescribeTable("should fail on invalid configs",
+			func(data, expectedError string) {
+				_, err := Parse([]byte(data))
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			},
+			Entry("invalid TOML", "[plugins", "failed parsing containerd config"),
+			Entry("version is no integer", `version = "2"`, "must be an integer"),
+			Entry("unsupported version", "version = 5", "unsupported containerd config version 5"),
+		)
+	})
+
+	Describe("#New", func() {
+		It("should create an empty config with the given version", func() {
+			config := New(2)
+			Expect(config.Version()).To(Equal(2))
+			Expect(marshal(config)).To(Equal("version = 2\n"))
+		})
+	})
+
+	DescribeTable("#Imports",
+		func(data string, expected bool) {
+			Expect(parse(data).Imports("/etc/containerd/conf.d/runtime-gvisor.toml", "/etc/containerd")).To(Equal(expected))
+		},
+		Entry("no imports", "version = 3", false),
+		Entry("import of the file", `imports = ["/etc/containerd/conf.d/runtime-gvisor.toml"]`, true),
+		Entry("import of the directory with a glob", `imports = ["/etc/containerd/conf.d/*.toml"]`, true),
+		Entry("relative import", `imports = ["conf.d/*.toml"]`, true),
+		Entry("import of another directory", `imports = ["/etc/containerd/certs.d/*.toml"]`, false),
+		Entry("import of other files", `imports = ["/etc/containerd/conf.d/*.conf"]`, false),
+	)
+
+	Describe("#SetRuntime", func() {
+		It("should insert the runtime and preserve unrelated content", func() {
+			config := parse(`version = 2
+# comments are dropped
+[plugins."io.containerd.grpc.v1.cri".containerd]
+  snapshotter = "overlayfs"
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+  runtime_type = "io.containerd.runc.v2"
+[plugins."io.containerd.grpc.v1.cri".registry]
+  config_path = "/etc/containerd/certs.d"
+`)
+
+			Expect(config.SetRuntime("runsc", runtime)).To(BeTrue())
+			Expect(marshal(config)).To(Equal(`version = 2
+
+[plugins]
+  [plugins."io.containerd.grpc.v1.cri"]
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+      snapshotter = "overlayfs"
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runsc]
+          runtime_type = "io.containerd.runsc.v1"
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runsc.options]
+            ConfigPath = "/etc/containerd/runsc.toml"
+            TypeUrl = "io.containerd.runsc.v1.options"
+    [plugins."io.containerd.grpc.v1.cri".registry]
+      config_path = "/etc/containerd/certs.d"
+`))
+		})
+
+		DescribeTable("should insert the runtime into the plugin of the config version",
+			func(data, expectedTable string) {
+				config := parse(data)
+				Expect(config.SetRuntime("runsc", runtime)).To(BeTrue())
+				Expect(marshal(config)).To(ContainSubstring("[" + expectedTable + "]\n"))
+			},
+			Entry("no version", "", `plugins.cri.containerd.runtimes.runsc`),
+			Entry("version 2", "version = 2", `plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runsc`),
+			Entry("version 3", "version = 3", `plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc`),
+			Entry("version 4", "version = 4", `plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc`),
+		)
+
+		It("should not change an up-to-date runtime", func() {
+			config := parse(`version = 3
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc]
+runtime_type = "io.containerd.runsc.v1"
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc.options]
+TypeUrl = "io.containerd.runsc.v1.options"
+ConfigPath = "/etc/containerd/runsc.toml"
+`)
+
+			Expect(config.SetRuntime("runsc", runtime)).To(BeFalse())
+		})
+
+		It("should update the options and preserve other keys of the runtime", func() {
+			config := parse(`version = 3
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc]
+runtime_type = "io.containerd.runsc.v1"
+pod_annotations = ["dev.gvisor.*"]
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc.options]
+TypeUrl = "io.containerd.runsc.v1.options"
+ConfigPath = "/etc/runsc.toml"
+BinaryName = "/usr/bin/runsc"
+`)
+
+			Expect(config.SetRuntime("runsc", runtime)).To(BeTrue())
+
+			actual, ok := config.Runtime("runsc")
+			Expect(ok).To(BeTrue())
+			Expect(actual).To(Equal(runtime))
+			Expect(marshal(config)).To(ContainSubstring(`pod_annotations = ["dev.gvisor.*"]`))
+		})
+
+		It("should fail if the path to the runtimes is no table", func() {
+			config := parse(`version = 2
+[plugins]
+"io.containerd.grpc.v1.cri" = "foo"
+`)
+
+			_, err := config.SetRuntime("runsc", runtime)
+			Expect(err).To(MatchError(ContainSubstring(`key "io.containerd.grpc.v1.cri" of containerd config is not a table`)))
+		})
+	})
+
+	Describe("#RemoveRuntime", func() {
+		It("should remove the runtime", func() {
+			config := parse(`version = 2
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+runtime_type = "io.containerd.runc.v2"
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runsc]
+runtime_type = "io.containerd.runsc.v1"
+`)
+
+			Expect(config.RemoveRuntime("runsc")).To(BeTrue())
+			_, ok := config.Runtime("runsc")
+			Expect(ok).To(BeFalse())
+			_, ok = config.Runtime("runc")
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should not change a config without the runtime", func() {
+			config := parse("version = 2\n")
+
+			Expect(config.RemoveRuntime("runsc")).To(BeFalse())
+			Expect(marshal(config)).To(Equal("version = 2\n"))
+		})
+	})
+
+	Describe("#RuntimeNames", func() {
+		It("should return the sorted names of the runtimes", func() {
+			config := parse(`version = 2
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runsc]
+runtime_type = "io.containerd.runsc.v1"
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+runtime_type = "io.containerd.runc.v2"
+`)
+
+			Expect(config.RuntimeNames()).To(Equal([]string{"runc", "runsc"}))
+		})
+
+		It("should return nothing for a config without runtimes", func() {
+			Expect(parse("version = 2\n").RuntimeNames()).To(BeEmpty())
+		})
+	})
+})
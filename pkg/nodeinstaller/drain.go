@@ -0,0 +1,299 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinstaller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// DrainTimeout is the duration after which draining a node is given up. Exposed for testing.
+	DrainTimeout = 10 * time.Minute
+	// DrainPollInterval is the interval in which the restart lock is tried to be acquired and the pods of a node are
+	// evicted. Exposed for testing.
+	DrainPollInterval = 5 * time.Second
+)
+
+// RestartCoordinator is called by the Installer before and after containerd is restarted on the node.
+type RestartCoordinator interface {
+	// BeforeRestart prepares the node for the restart of containerd. The node is left unchanged if it fails.
+	BeforeRestart(ctx context.Context) error
+	// AfterRestart is called after containerd has been restarted, also if the restart failed.
+	AfterRestart(ctx context.Context) error
+}
+
+// NodeDrainer cordons and drains a node before containerd is restarted and uncordons it afterwards. Pods are evicted,
+// i.e. PodDisruptionBudgets are respected. The number of nodes of a worker pool which are drained at the same time is
+// limited by a set of leases in the kube-system namespace, a node has to hold one of them while it is drained.
+type NodeDrainer struct {
+	log           logr.Logger
+	client        client.Client
+	nodeName      string
+	workerPool    string
+	maxConcurrent int
+
+	cordoned bool
+	lease    *coordinationv1.Lease
+}
+
+var _ RestartCoordinator = (*NodeDrainer)(nil)
+
+// NewNodeDrainer creates a new NodeDrainer for the given node of the given worker pool, of which at most maxConcurrent
+// nodes are drained at the same time.
+func NewNodeDrainer(log logr.Logger, c client.Client, nodeName, workerPool string, maxConcurrent int) *NodeDrainer {
+	return &NodeDrainer{
+		log:           log,
+		client:        c,
+		nodeName:      nodeName,
+		workerPool:    workerPool,
+		maxConcurrent: maxConcurrent,
+	}
+}
+
+// BeforeRestart acquires the restart lock of the worker pool, cordons the node and evicts its pods.
+func (d *NodeDrainer) BeforeRestart(ctx context.Context) error {
+	if err := d.acquireLock(ctx); err != nil {
+		return fmt.Errorf("failed acquiring restart lock of worker pool %s: %w", d.workerPool, err)
+	}
+
+	err := d.cordon(ctx)
+	if err == nil {
+		err = d.drain(ctx)
+	}
+	if err != nil {
+		return errors.Join(err, d.AfterRestart(ctx))
+	}
+	return nil
+}
+
+// AfterRestart uncordons the node if it has been cordoned by BeforeRestart and releases the restart lock.
+func (d *NodeDrainer) AfterRestart(ctx context.Context) error {
+	var errs []error
+	if d.cordoned {
+		if err := d.setUnschedulable(ctx, false); err != nil {
+			errs = append(errs, fmt.Errorf("failed uncordoning node: %w", err))
+		} else {
+			d.cordoned = false
+			d.log.Info("Uncordoned node", "node", d.nodeName)
+		}
+	}
+
+	if d.lease != nil {
+		if err := d.releaseLock(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed releasing restart lock: %w", err))
+		} else {
+			d.lease = nil
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d *NodeDrainer) acquireLock(ctx context.Context) error {
+	for {
+		for i := range d.maxConcurrent {
+			acquired, err := d.tryAcquireLease(ctx, fmt.Sprintf("gvisor-containerd-restart-%s-%d", d.workerPool, i))
+			if err != nil {
+				return err
+			}
+			if acquired {
+				d.log.Info("Acquired restart lock", "lease", client.ObjectKeyFromObject(d.lease))
+				return nil
+			}
+		}
+
+		d.log.Info("Waiting for other nodes of the worker pool to restart containerd", "workerPool", d.workerPool)
+		if err := sleep(ctx, DrainPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// tryAcquireLease acquires the lease with the given name if it is not held by another node. Leases of nodes which did
+// not release them, e.g. because the installer has been killed, expire once a drain would have timed out.
+func (d *NodeDrainer) tryAcquireLease(ctx context.Context, name string) (bool, error) {
+	now := metav1.NewMicroTime(time.Now())
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceSystem}}
+	spec := coordinationv1.LeaseSpec{
+		HolderIdentity:       ptr.To(d.nodeName),
+		LeaseDurationSeconds: ptr.To(int32((DrainTimeout + 5*time.Minute).Seconds())),
+		AcquireTime:          &now,
+		RenewTime:            &now,
+	}
+
+	if err := d.client.Get(ctx, client.ObjectKeyFromObject(lease), lease); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+
+		lease.Spec = spec
+		if err := d.client.Create(ctx, lease); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		d.lease = lease
+		return true, nil
+	}
+
+	if holder := ptr.Deref(lease.Spec.HolderIdentity, ""); holder != "" && holder != d.nodeName && !leaseExpired(lease, now.Time) {
+		return false, nil
+	}
+
+	// The optimistic lock prevents that two nodes acquire the same lease.
+	patch := client.MergeFromWithOptions(lease.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	lease.Spec = spec
+	if err := d.client.Patch(ctx, lease, patch); err != nil {
+		if apierrors.IsConflict(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	d.lease = lease
+	return true, nil
+}
+
+func (d *NodeDrainer) releaseLock(ctx context.Context) error {
+	patch := client.MergeFromWithOptions(d.lease.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	d.lease.Spec.HolderIdentity = nil
+	if err := d.client.Patch(ctx, d.lease, patch); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func leaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second).Before(now)
+}
+
+// cordon marks the node as unschedulable. Nodes which have already been cordoned, e.g. by an operator, are left
+// cordoned afterwards.
+func (d *NodeDrainer) cordon(ctx context.Context) error {
+	node := &corev1.Node{}
+	if err := d.client.Get(ctx, client.ObjectKey{Name: d.nodeName}, node); err != nil {
+		return err
+	}
+	if node.Spec.Unschedulable {
+		return nil
+	}
+
+	if err := d.setUnschedulable(ctx, true); err != nil {
+		return fmt.Errorf("failed cordoning node: %w", err)
+	}
+	d.cordoned = true
+	d.log.Info("Cordoned node", "node", d.nodeName)
+	return nil
+}
+
+func (d *NodeDrainer) setUnschedulable(ctx context.Context, unschedulable bool) error {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: d.nodeName}}
+	patch := fmt.Appendf(nil, `{"spec":{"unschedulable":%t}}`, unschedulable)
+	return d.client.Patch(ctx, node, client.RawPatch(types.MergePatchType, patch))
+}
+
+// drain evicts the pods of the node until all of them are gone. Evictions which are refused because of a
+// PodDisruptionBudget are retried until the DrainTimeout is reached.
+func (d *NodeDrainer) drain(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, DrainTimeout)
+	defer cancel()
+
+	for {
+		pods, err := d.podsToEvict(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			d.log.Info("Drained node", "node", d.nodeName)
+			return nil
+		}
+
+		for _, pod := range pods {
+			if err := d.client.SubResource("eviction").Create(ctx, &pod, &policyv1.Eviction{}); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				if apierrors.IsTooManyRequests(err) {
+					d.log.Info("Eviction of pod is refused, retrying", "pod", client.ObjectKeyFromObject(&pod), "reason", err.Error())
+					continue
+				}
+				return fmt.Errorf("failed evicting pod %s: %w", client.ObjectKeyFromObject(&pod), err)
+			}
+			d.log.Info("Evicted pod", "pod", client.ObjectKeyFromObject(&pod))
+		}
+
+		if err := sleep(ctx, DrainPollInterval); err != nil {
+			names := make([]string, 0, len(pods))
+			for _, pod := range pods {
+				names = append(names, client.ObjectKeyFromObject(&pod).String())
+			}
+			slices.Sort(names)
+			return fmt.Errorf("timed out draining node %s, remaining pods: %v", d.nodeName, names)
+		}
+	}
+}
+
+// podsToEvict returns the pods of the node which have to be evicted. Like `kubectl drain --ignore-daemonsets`, pods
+// of DaemonSets (including the installer) and static pods are not evicted. Like `kubectl drain` without `--force`,
+// it fails if pods are not managed by a controller, as they would not be recreated after their eviction.
+func (d *NodeDrainer) podsToEvict(ctx context.Context) ([]corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := d.client.List(ctx, podList, client.MatchingFields{"spec.nodeName": d.nodeName}); err != nil {
+		return nil, fmt.Errorf("failed listing pods of node %s: %w", d.nodeName, err)
+	}
+
+	pods := slices.DeleteFunc(podList.Items, func(pod corev1.Pod) bool {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			return true
+		}
+		if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+			return true
+		}
+		controller := metav1.GetControllerOf(&pod)
+		return controller != nil && controller.Kind == "DaemonSet"
+	})
+
+	var unmanaged []string
+	for _, pod := range pods {
+		if metav1.GetControllerOf(&pod) == nil {
+			unmanaged = append(unmanaged, client.ObjectKeyFromObject(&pod).String())
+		}
+	}
+	if len(unmanaged) > 0 {
+		slices.Sort(unmanaged)
+		return nil, fmt.Errorf("cannot drain node %s, pods are not managed by a controller: %v", d.nodeName, unmanaged)
+	}
+
+	return pods, nil
+}
+
+// sleep waits for the given duration. It returns an error if the context is cancelled before.
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
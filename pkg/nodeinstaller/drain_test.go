@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinstaller_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	"github.com/gardener/gardener/pkg/utils/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	. "github.com/gardener/gardener-extension-runtime-gvisor/pkg/nodeinstaller"
+)
+
+var _ = Describe("NodeDrainer", func() {
+	var (
+		ctx = context.Background()
+		log = logf.Log.WithName("test")
+
+		interceptorFuncs interceptor.Funcs
+		c                client.Client
+		drainer          *NodeDrainer
+	)
+
+	createPod := func(name, nodeName string, mutate func(*corev1.Pod)) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "rs"}}, appsv1.SchemeGroupVersion.WithKind("ReplicaSet"))},
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+		}
+		if mutate != nil {
+			mutate(pod)
+		}
+		ExpectWithOffset(1, c.Create(ctx, pod)).To(Succeed())
+	}
+
+	podNames := func() []string {
+		podList := &corev1.PodList{}
+		ExpectWithOffset(1, c.List(ctx, podList)).To(Succeed())
+		var names []string
+		for _, pod := range podList.Items {
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+
+	unschedulable := func() bool {
+		node := &corev1.Node{}
+		ExpectWithOffset(1, c.Get(ctx, client.ObjectKey{Name: "node-a"}, node)).To(Succeed())
+		return node.Spec.Unschedulable
+	}
+
+	lease := func(name string) *coordinationv1.Lease {
+		lease := &coordinationv1.Lease{}
+		ExpectWithOffset(1, c.Get(ctx, client.ObjectKey{Namespace: "kube-system", Name: name}, lease)).To(Succeed())
+		return lease
+	}
+
+	createLease := func(name, holder string, renewTime time.Time) {
+		ExpectWithOffset(1, c.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kube-system"},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(holder),
+				LeaseDurationSeconds: ptr.To[int32](900),
+				RenewTime:            ptr.To(metav1.NewMicroTime(renewTime)),
+			},
+		})).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		DeferCleanup(test.WithVars(
+			&DrainPollInterval, 10*time.Millisecond,
+			&DrainTimeout, 200*time.Millisecond,
+		))
+
+		interceptorFuncs = interceptor.Funcs{}
+	})
+
+	JustBeforeEach(func() {
+		c = fakeclient.NewClientBuilder().
+			WithScheme(kubernetes.ShootScheme).
+			WithIndex(&corev1.Pod{}, "spec.nodeName", func(obj client.Object) []string {
+				return []string{obj.(*corev1.Pod).Spec.NodeName}
+			}).
+			WithInterceptorFuncs(interceptorFuncs).
+			Build()
+		Expect(c.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}})).To(Succeed())
+		drainer = NewNodeDrainer(log, c, "node-a", "worker", 1)
+	})
+
+	It("should cordon and drain the node and uncordon it afterwards", func() {
+		createPod("evicted", "node-a", nil)
+		createPod("other-node", "node-b", nil)
+		createPod("daemonset", "node-a", func(pod *corev1.Pod) {
+			pod.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "ds"}}, appsv1.SchemeGroupVersion.WithKind("DaemonSet"))}
+		})
+		createPod("static", "node-a", func(pod *corev1.Pod) {
+			pod.Annotations = map[string]string{corev1.MirrorPodAnnotationKey: "hash"}
+		})
+		createPod("completed", "node-a", func(pod *corev1.Pod) {
+			pod.Status.Phase = corev1.PodSucceeded
+		})
+
+		Expect(drainer.BeforeRestart(ctx)).To(Succeed())
+		Expect(unschedulable()).To(BeTrue())
+		Expect(podNames()).To(ConsistOf("other-node", "daemonset", "static", "completed"))
+		Expect(lease("gvisor-containerd-restart-worker-0").Spec.HolderIdentity).To(HaveValue(Equal("node-a")))
+
+		Expect(drainer.AfterRestart(ctx)).To(Succeed())
+		Expect(unschedulable()).To(BeFalse())
+		Expect(lease("gvisor-containerd-restart-worker-0").Spec.HolderIdentity).To(BeNil())
+	})
+
+	It("should fail and leave the node unchanged if pods are not managed by a controller", func() {
+		createPod("managed", "node-a", nil)
+		createPod("unmanaged", "node-a", func(pod *corev1.Pod) {
+			pod.OwnerReferences = nil
+		})
+		createPod("unmanaged-completed", "node-a", func(pod *corev1.Pod) {
+			pod.OwnerReferences = nil
+			pod.Status.Phase = corev1.PodFailed
+		})
+
+		Expect(drainer.BeforeRestart(ctx)).To(MatchError(ContainSubstring("cannot drain node node-a, pods are not managed by a controller: [default/unmanaged]")))
+		Expect(unschedulable()).To(BeFalse())
+		Expect(podNames()).To(ConsistOf("managed", "unmanaged", "unmanaged-completed"))
+		Expect(lease("gvisor-containerd-restart-worker-0").Spec.HolderIdentity).To(BeNil())
+	})
+
+	It("should not uncordon a node which has been cordoned before", func() {
+		Expect(c.Patch(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}, client.RawPatch("application/merge-patch+json", []byte(`{"spec":{"unschedulable":true}}`)))).To(Succeed())
+
+		Expect(drainer.BeforeRestart(ctx)).To(Succeed())
+		Expect(drainer.AfterRestart(ctx)).To(Succeed())
+		Expect(unschedulable()).To(BeTrue())
+	})
+
+	It("should wait for the restart lock held by another node", func() {
+		createLease("gvisor-containerd-restart-worker-0", "node-b", time.Now())
+		createPod("evicted", "node-a", nil)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		Expect(drainer.BeforeRestart(timeoutCtx)).To(MatchError(ContainSubstring("failed acquiring restart lock of worker pool worker")))
+		Expect(unschedulable()).To(BeFalse())
+		Expect(podNames()).To(ConsistOf("evicted"))
+		Expect(lease("gvisor-containerd-restart-worker-0").Spec.HolderIdentity).To(HaveValue(Equal("node-b")))
+	})
+
+	It("should acquire a free restart lock if more nodes may restart at the same time", func() {
+		createLease("gvisor-containerd-restart-worker-0", "node-b", time.Now())
+		drainer = NewNodeDrainer(log, c, "node-a", "worker", 2)
+
+		Expect(drainer.BeforeRestart(ctx)).To(Succeed())
+		Expect(lease("gvisor-containerd-restart-worker-1").Spec.HolderIdentity).To(HaveValue(Equal("node-a")))
+	})
+
+	It("should take over an expired restart lock", func() {
+		createLease("gvisor-containerd-restart-worker-0", "node-b", time.Now().Add(-time.Hour))
+
+		Expect(drainer.BeforeRestart(ctx)).To(Succeed())
+		Expect(lease("gvisor-containerd-restart-worker-0").Spec.HolderIdentity).To(HaveValue(Equal("node-a")))
+	})
+
+	Context("evictions refused by a PodDisruptionBudget", func() {
+		BeforeEach(func() {
+			interceptorFuncs.SubResourceCreate = func(_ context.Context, _ client.Client, subResourceName string, _ client.Object, _ client.Object, _ ...client.SubResourceCreateOption) error {
+				Expect(subResourceName).To(Equal("eviction"))
+				return apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+			}
+		})
+
+		It("should give up and leave the node unchanged", func() {
+			createPod("protected", "node-a", nil)
+
+			Expect(drainer.BeforeRestart(ctx)).To(MatchError(ContainSubstring("timed out draining node node-a, remaining pods: [default/protected]")))
+			Expect(unschedulable()).To(BeFalse())
+			Expect(podNames()).To(ConsistOf("protected"))
+			Expect(lease("gvisor-containerd-restart-worker-0").Spec.HolderIdentity).To(BeNil())
+		})
+	})
+
+	Context("evictions failing unexpectedly", func() {
+		BeforeEach(func() {
+			interceptorFuncs.SubResourceCreate = func(context.Context, client.Client, string, client.Object, client.Object, ...client.SubResourceCreateOption) error {
+				return apierrors.NewForbidden(schema.GroupResource{Resource: "pods/eviction"}, "protected", nil)
+			}
+		})
+
+		It("should fail and leave the node unchanged", func() {
+			createPod("protected", "node-a", nil)
+
+			Expect(drainer.BeforeRestart(ctx)).To(MatchError(ContainSubstring("failed evicting pod default/protected")))
+			Expect(unschedulable()).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,477 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinstaller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/nodeinstaller/containerdconfig"
+)
+
+const (
+	// RunscBinaryName is the name of the runsc binary.
+	RunscBinaryName = "runsc"
+	// ShimBinaryName is the name of the containerd shim binary for runsc.
+	ShimBinaryName = "containerd-shim-runsc-v1"
+	// ChecksumsFileName is the name of the manifest in the content directory which contains the expected sha512
+	// checksums of the binaries.
+	ChecksumsFileName = "SHA512SUMS"
+	// InstalledChecksumsFileName is the name of the manifest in the binary directory on the node which contains the
+	// sha512 checksums the installed binaries have been installed with.
+	InstalledChecksumsFileName = "runsc.SHA512SUMS"
+
+	// ContainerdConfigPath is the path of the containerd config file on the node.
+	ContainerdConfigPath = "/etc/containerd/config.toml"
+	// ContainerdDropInPath is the path of the containerd drop-in config with the runsc runtime on the node.
+	ContainerdDropInPath = "/etc/containerd/conf.d/runtime-gvisor.toml"
+	// RunscConfigPath is the path of the runsc config file on the node.
+	RunscConfigPath = "/etc/containerd/runsc.toml"
+	// profileRunscConfigPattern is the pattern of the paths of the runsc config files of the profiles on the node.
+	profileRunscConfigPattern = "/etc/containerd/runsc-*.toml"
+	// KVMDevicePath is the path of the KVM device on the node.
+	KVMDevicePath = "/dev/kvm"
+)
+
+var (
+	// RestartContainerd restarts containerd on the node whose root filesystem is mounted at the given host root.
+	// Exposed for testing.
+	RestartContainerd = restartContainerd
+	// KVMUsable returns whether the KVM device of the node whose root filesystem is mounted at the given host root can
+	// be used. Exposed for testing.
+	KVMUsable = kvmUsable
+	// RunscVersion returns the version of the runsc binary at the given path. Exposed for testing.
+	RunscVersion = runscVersion
+	// VerifyContainerd verifies that containerd is running on the node whose root filesystem is mounted at the given
+	// host root and that its effective config contains the given runtimes. Exposed for testing.
+	VerifyContainerd = verifyContainerd
+	// StartTestSandbox starts a sandbox with the runsc binary at the given path on the node whose root filesystem is
+	// mounted at the given host root. Exposed for testing.
+	StartTestSandbox = startTestSandbox
+	// WriteBinary writes the content of a binary to the given path on the node. Exposed for testing.
+	WriteBinary = writeFileAtomically
+)
+
+// Options are the options for installing gVisor on a node.
+type Options struct {
+	// HostRoot is the path at which the root filesystem of the node is mounted.
+	HostRoot string
+	// ContentDir is the directory containing the runsc and containerd-shim-runsc-v1 binaries to install.
+	ContentDir string
+	// BinDir is the directory on the node into which the binaries are installed.
+	BinDir string
+	// RunscConfigFlags are the rendered flags of the `[runsc_config]` section of runsc.toml.
+	RunscConfigFlags string
+	// Platform is the configured gVisor platform. In `auto` mode, it is determined on the node and added to the
+	// runsc config flags.
+	Platform config.Platform
+	// Profiles are the rendered flags of the `[runsc_config]` section of the runsc configs of the profiles by their
+	// name. Each profile is configured as its own runtime in containerd, see ProfileRuntimeName.
+	Profiles map[string]string
+	// ContainerdConfigMode is the mode in which the runsc runtime is configured in containerd.
+	ContainerdConfigMode gvisor.ContainerdConfigMode
+	// TestSandbox starts a test sandbox after the installation to verify that runsc works on the node.
+	TestSandbox bool
+	// RestartCoordinator is called before and after containerd is restarted, e.g. to drain the node. Optional.
+	RestartCoordinator RestartCoordinator
+	// TamperReporter is called with the installed binaries which have been modified on the node before they are
+	// reinstalled. Optional.
+	TamperReporter TamperReporter
+}
+
+// TamperReporter reports installed binaries which have been modified on the node, i.e. which do not match the
+// checksums they have been installed with.
+type TamperReporter interface {
+	// ReportTampered reports the given paths of modified binaries on the node.
+	ReportTampered(ctx context.Context, paths []string) error
+}
+
+// Result is the result of an installation or uninstallation.
+type Result struct {
+	// Platform is the gVisor platform used on the node. It is empty after an uninstallation.
+	Platform config.Platform
+	// RunscVersion is the version of the installed runsc binary. It is empty if the binaries are not installed by the
+	// Installer.
+	RunscVersion string
+	// ConfigHash is the hash of the runsc config written to the node. It is empty after an uninstallation.
+	ConfigHash string
+	// ContainerdRestarted is true if containerd was restarted to apply the configuration.
+	ContainerdRestarted bool
+	// Changed are the paths of the binaries and config files which have been written or removed by the installation,
+	// because they were missing or differed from the expected content.
+	Changed []string
+	// OutdatedSandboxes is the number of running sandboxes which still use replaced binaries, see
+	// Installer.OutdatedSandboxes. It is not set after an uninstallation.
+	OutdatedSandboxes int
+	// Profiles are the sorted names of the profiles configured on the node. It is empty after an uninstallation.
+	Profiles []string
+}
+
+// Installer installs gVisor on a node.
+type Installer struct {
+	log  logr.Logger
+	opts Options
+}
+
+// ProfileRunscConfigPath returns the path of the runsc config file of the profile with the given name on the node.
+func ProfileRunscConfigPath(profile string) string {
+	return strings.Replace(profileRunscConfigPattern, "*", profile, 1)
+}
+
+// New creates a new Installer.
+func New(log logr.Logger, opts Options) *Installer {
+	return &Installer{
+		log:  log,
+		opts: opts,
+	}
+}
+
+// Install installs the gVisor binaries on the node, configures the runsc runtime in containerd, writes the runsc
+// config, restarts containerd if its configuration was changed and verifies that containerd serves the runsc runtime.
+func (i *Installer) Install(ctx context.Context) (*Result, error) {
+	return i.install(ctx, true)
+}
+
+// install installs gVisor on the node, see Install. If the test sandbox is enabled, it is started if forceTestSandbox
+// is true or any file has been changed.
+func (i *Installer) install(ctx context.Context, forceTestSandbox bool) (_ *Result, err error) {
+	binDir := i.hostPath(i.opts.BinDir)
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed creating binary directory %s: %w", binDir, err)
+	}
+
+	platform := i.platform()
+	runscVersion, installedBinaries, replacedBinaries, err := i.installBinaries(ctx, binDir, platform)
+	// A failure after a binary has been replaced leaves the node with binaries which cannot be used, e.g. because
+	// containerd cannot start sandboxes with them, hence all replaced binaries are rolled back on any later failure.
+	defer func() {
+		if err != nil {
+			err = i.rollbackBinaries(binDir, replacedBinaries, err)
+		}
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	changedContainerdConfigs, err := i.configureContainerd()
+	if err != nil {
+		return nil, fmt.Errorf("failed configuring containerd: %w", err)
+	}
+
+	changedRunscConfigs, configHash, err := i.writeRunscConfigs(platform)
+	if err != nil {
+		return nil, fmt.Errorf("failed writing runsc config: %w", err)
+	}
+
+	result := &Result{Platform: platform, RunscVersion: runscVersion, ConfigHash: configHash, Profiles: i.profiles()}
+	result.Changed = slices.Concat(installedBinaries, changedContainerdConfigs, changedRunscConfigs)
+	if len(changedContainerdConfigs) > 0 || len(changedRunscConfigs) > 0 {
+		if err := i.restartContainerd(ctx); err != nil {
+			return nil, err
+		}
+		result.ContainerdRestarted = true
+	}
+
+	if err := VerifyContainerd(ctx, i.opts.HostRoot, slices.Sorted(maps.Keys(i.runtimes()))); err != nil {
+		return nil, fmt.Errorf("failed verifying containerd: %w", err)
+	}
+
+	if i.opts.TestSandbox && (forceTestSandbox || len(result.Changed) > 0) {
+		if err := StartTestSandbox(ctx, i.opts.HostRoot, filepath.Join(i.opts.BinDir, RunscBinaryName), platform); err != nil {
+			return nil, fmt.Errorf("failed starting test sandbox: %w", err)
+		}
+		i.log.Info("Started test sandbox")
+	}
+
+	if result.OutdatedSandboxes, err = i.OutdatedSandboxes(); err != nil {
+		return nil, fmt.Errorf("failed counting outdated sandboxes: %w", err)
+	}
+	if result.OutdatedSandboxes > 0 {
+		i.log.Info("Running sandboxes use replaced binaries and have to be restarted to use the installed ones", "count", result.OutdatedSandboxes)
+	}
+
+	// The manifest is only recorded for installed binaries, rolled back binaries keep the checksums of their release.
+	if err := i.recordChecksums(binDir); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// rollbackBinaries restores the previous binaries of the given replaced binaries after the installation failed with
+// the given error. It returns the error of the installation, amended by the result of the rollback.
+func (i *Installer) rollbackBinaries(binDir string, replaced []string, err error) error {
+	if len(replaced) == 0 {
+		return err
+	}
+
+	i.log.Error(err, "Installation failed after binaries have been replaced, rolling back", "binaries", replaced)
+	for _, name := range replaced {
+		if rollbackErr := rollbackBinary(filepath.Join(binDir, name)); rollbackErr != nil {
+			return fmt.Errorf("%w, failed rolling back binary %s: %w", err, name, rollbackErr)
+		}
+		i.log.Info("Rolled back binary", "name", name, "dir", i.opts.BinDir)
+	}
+	return fmt.Errorf("%w, rolled back to the previous binaries", err)
+}
+
+// recordChecksums records the checksum manifest of the installed binaries, which is used to detect binaries that have
+// been modified on the node.
+func (i *Installer) recordChecksums(binDir string) error {
+	manifest, err := os.ReadFile(filepath.Join(i.opts.ContentDir, ChecksumsFileName))
+	if err != nil {
+		return fmt.Errorf("failed reading checksum manifest: %w", err)
+	}
+	if _, err := writeFileIfChanged(filepath.Join(binDir, InstalledChecksumsFileName), manifest, 0644); err != nil {
+		return fmt.Errorf("failed recording checksums of installed binaries: %w", err)
+	}
+	return nil
+}
+
+// installBinaries installs the gVisor binaries into the given directory and verifies them. The previous binaries are
+// kept next to the installed ones, so that they can be restored if the installation fails, see rollbackBinaries, and
+// a broken release does not prevent the node from starting sandboxes. It returns the version of the installed runsc
+// binary, the paths of the binaries which have been installed and the names of the binaries which have been replaced,
+// also if an error is returned.
+func (i *Installer) installBinaries(ctx context.Context, binDir string, platform config.Platform) (string, []string, []string, error) {
+	binaries := []string{RunscBinaryName, ShimBinaryName}
+
+	expectedChecksums, err := readChecksums(filepath.Join(i.opts.ContentDir, ChecksumsFileName))
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed reading checksum manifest: %w", err)
+	}
+	// Nothing is installed if the binaries in the image are corrupted or have been tampered with.
+	for _, name := range binaries {
+		if err := expectedChecksums.verify(name, filepath.Join(i.opts.ContentDir, name)); err != nil {
+			return "", nil, nil, fmt.Errorf("refusing to install, verification of shipped binary %s failed: %w", name, err)
+		}
+	}
+
+	// Installed binaries which differ from the shipped ones are either binaries of a previous release or have been
+	// modified on the node. The checksums recorded by the previous installation tell them apart.
+	installedChecksumsPath := filepath.Join(binDir, InstalledChecksumsFileName)
+	installedChecksums, err := readChecksums(installedChecksumsPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		i.log.Error(err, "Failed reading checksums of installed binaries, skipping the detection of modified binaries")
+	}
+	if tampered := i.tamperedBinaries(binDir, binaries, expectedChecksums, installedChecksums); len(tampered) > 0 {
+		i.log.Info("Installed binaries have been modified on the node, reinstalling", "binaries", tampered)
+		if i.opts.TamperReporter != nil {
+			if err := i.opts.TamperReporter.ReportTampered(ctx, tampered); err != nil {
+				return "", nil, nil, fmt.Errorf("failed reporting modified binaries %s: %w", strings.Join(tampered, ", "), err)
+			}
+		}
+	}
+
+	var installed, replaced []string
+	for _, name := range binaries {
+		// The shim is a long-running process, the new binary is only used for new sandboxes. Killing the running shims
+		// is not an option as it would kill their containers.
+		source, target := filepath.Join(i.opts.ContentDir, name), filepath.Join(binDir, name)
+		updated, hadPrevious, err := installBinary(source, target)
+		if hadPrevious {
+			replaced = append(replaced, name)
+		}
+		if err != nil {
+			return "", nil, replaced, fmt.Errorf("failed installing binary %s: %w", name, err)
+		}
+		if updated {
+			i.log.Info("Installed binary", "name", name, "dir", i.opts.BinDir)
+			installed = append(installed, filepath.Join(i.opts.BinDir, name))
+		} else {
+			i.log.Info("Binary is up to date", "name", name)
+		}
+
+		// Installed binaries which have been modified on the node are replaced above, hence a mismatch means that the
+		// binary has been corrupted while it was written.
+		if err := expectedChecksums.verify(name, target); err != nil {
+			return "", nil, replaced, fmt.Errorf("verification of installed binary %s failed: %w", name, err)
+		}
+	}
+
+	runscVersion, err := i.verifyRunsc(ctx, binDir, platform, len(replaced) > 0)
+	if err != nil {
+		return "", nil, replaced, err
+	}
+	i.log.Info("Installed runsc", "version", runscVersion)
+
+	return runscVersion, installed, replaced, nil
+}
+
+// tamperedBinaries returns the paths on the node of the installed binaries which neither match the checksums of the
+// binaries to install nor the checksums they have been installed with. Missing binaries are not reported.
+func (i *Installer) tamperedBinaries(binDir string, binaries []string, expectedChecksums, installedChecksums checksums) []string {
+	var tampered []string
+	for _, name := range binaries {
+		installedChecksum, ok := installedChecksums[name]
+		if !ok {
+			continue
+		}
+		actual, err := fileChecksum(filepath.Join(binDir, name))
+		if err != nil {
+			continue
+		}
+		if actual != installedChecksum && actual != expectedChecksums[name] {
+			tampered = append(tampered, filepath.Join(i.opts.BinDir, name))
+		}
+	}
+	return tampered
+}
+
+// verifyRunsc executes the installed runsc binary to make sure that it is not corrupted and can be run on the node.
+// Replaced binaries are additionally smoke tested by starting a sandbox.
+func (i *Installer) verifyRunsc(ctx context.Context, binDir string, platform config.Platform, smokeTest bool) (string, error) {
+	runscVersion, err := RunscVersion(ctx, filepath.Join(binDir, RunscBinaryName))
+	if err != nil {
+		return "", fmt.Errorf("failed determining runsc version: %w", err)
+	}
+
+	if smokeTest {
+		if err := StartTestSandbox(ctx, i.opts.HostRoot, filepath.Join(i.opts.BinDir, RunscBinaryName), platform); err != nil {
+			return "", fmt.Errorf("failed starting smoke test sandbox with runsc %s: %w", runscVersion, err)
+		}
+		i.log.Info("Started smoke test sandbox", "version", runscVersion)
+	}
+	return runscVersion, nil
+}
+
+// restartContainerd restarts containerd on the node. The RestartCoordinator is called before and after the restart.
+func (i *Installer) restartContainerd(ctx context.Context) error {
+	if i.opts.RestartCoordinator != nil {
+		if err := i.opts.RestartCoordinator.BeforeRestart(ctx); err != nil {
+			return fmt.Errorf("failed preparing node for containerd restart: %w", err)
+		}
+	}
+
+	i.log.Info("Restarting containerd")
+	err := RestartContainerd(ctx, i.opts.HostRoot)
+	if err != nil {
+		err = fmt.Errorf("failed restarting containerd: %w", err)
+	}
+
+	if i.opts.RestartCoordinator != nil {
+		if afterErr := i.opts.RestartCoordinator.AfterRestart(ctx); afterErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed finishing containerd restart: %w", afterErr))
+		}
+	}
+	return err
+}
+
+// platform returns the gVisor platform to use on the node.
+func (i *Installer) platform() config.Platform {
+	if i.opts.Platform != config.PlatformAuto {
+		return i.opts.Platform
+	}
+
+	if KVMUsable(i.opts.HostRoot) {
+		i.log.Info("KVM is usable, using platform kvm")
+		return config.PlatformKVM
+	}
+
+	i.log.Info("KVM is not usable, falling back to platform systrap")
+	return config.PlatformSystrap
+}
+
+// profiles returns the sorted names of the configured profiles.
+func (i *Installer) profiles() []string {
+	if len(i.opts.Profiles) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(i.opts.Profiles))
+}
+
+func (i *Installer) hostPath(path string) string {
+	return filepath.Join(i.opts.HostRoot, path)
+}
+
+func restartContainerd(ctx context.Context, hostRoot string) error {
+	_, err := runOnHost(ctx, hostRoot, "systemctl", "restart", "containerd")
+	return err
+}
+
+func verifyContainerd(ctx context.Context, hostRoot string, runtimeNames []string) error {
+	if _, err := runOnHost(ctx, hostRoot, "systemctl", "is-active", "containerd"); err != nil {
+		return fmt.Errorf("containerd is not running: %w", err)
+	}
+
+	// The dumped config contains the imported drop-in configs, i.e. it is the config containerd is running with.
+	output, err := runOnHost(ctx, hostRoot, "containerd", "config", "dump")
+	if err != nil {
+		return fmt.Errorf("failed dumping containerd config: %w", err)
+	}
+
+	containerdConfig, err := containerdconfig.Parse(output)
+	if err != nil {
+		return fmt.Errorf("failed parsing dumped containerd config: %w", err)
+	}
+	for _, name := range runtimeNames {
+		if _, ok := containerdConfig.Runtime(name); !ok {
+			return fmt.Errorf("runtime %s is missing in the containerd config", name)
+		}
+	}
+	return nil
+}
+
+// startTestSandbox runs `true` in a sandbox without network on the node. The root filesystem of the node is used as
+// root filesystem of the sandbox.
+func startTestSandbox(ctx context.Context, hostRoot, runscPath string, platform config.Platform) error {
+	_, err := runOnHost(ctx, hostRoot, runscPath, "--root=/run/gvisor-node-installer", "--network=none", "--platform="+string(platform), "do", "true")
+	return err
+}
+
+// runOnHost runs the given command in the root filesystem of the node and returns its output.
+func runOnHost(ctx context.Context, hostRoot string, command ...string) ([]byte, error) {
+	output, err := exec.CommandContext(ctx, "chroot", append([]string{hostRoot}, command...)...).CombinedOutput() // #nosec G204 -- host root is configured by the extension.
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, output)
+	}
+	return output, nil
+}
+
+// runscVersion runs `runsc --version` and returns the version from the first line of its output, e.g.
+// `runsc version release-20250414.0`.
+func runscVersion(ctx context.Context, path string) (string, error) {
+	output, err := exec.CommandContext(ctx, path, "--version").CombinedOutput() // #nosec G204 -- path is not user provided.
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, output)
+	}
+
+	firstLine, _, _ := strings.Cut(string(output), "\n")
+	version, ok := strings.CutPrefix(strings.TrimSpace(firstLine), "runsc version ")
+	if !ok || version == "" {
+		return "", fmt.Errorf("unexpected output of runsc --version: %q", firstLine)
+	}
+	return version, nil
+}
+
+// kvmUsable checks if the KVM device exists and can be opened. It is missing on virtual machines without nested
+// virtualization and cannot be opened if the kvm module is not usable.
+func kvmUsable(hostRoot string) bool {
+	path := filepath.Join(hostRoot, KVMDevicePath)
+
+	info, err := os.Stat(path)
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0) // #nosec G304 -- path is not user provided.
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+	return true
+}
@@ -0,0 +1,1095 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinstaller_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/gardener/gardener/pkg/utils/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+	. "github.com/gardener/gardener-extension-runtime-gvisor/pkg/nodeinstaller"
+)
+
+var (
+	// kvmUsable is the original implementation of KVMUsable which is replaced in the tests.
+	kvmUsable = KVMUsable
+	// runscVersion is the original implementation of RunscVersion which is replaced in the tests.
+	runscVersion = RunscVersion
+)
+
+func sha256Hex(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+func sha512Hex(content string) string {
+	hash := sha512.Sum512([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+var _ = Describe("Installer", func() {
+	var (
+		ctx = context.Background()
+		log = logf.Log.WithName("test")
+
+		hostRoot   string
+		contentDir string
+		opts       Options
+
+		restarts         int
+		verifiedRuntimes []string
+		kvm              bool
+		smokeTests       int
+		smokeTestFn      func() error
+	)
+
+	readHostFile := func(path string) string {
+		content, err := os.ReadFile(filepath.Join(hostRoot, path))
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return string(content)
+	}
+
+	writeHostFile := func(path, content string) {
+		ExpectWithOffset(1, os.MkdirAll(filepath.Dir(filepath.Join(hostRoot, path)), 0755)).To(Succeed())
+		ExpectWithOffset(1, os.WriteFile(filepath.Join(hostRoot, path), []byte(content), 0644)).To(Succeed())
+	}
+
+	// writeContent writes the binary with the given name into the content directory and updates the checksum manifest.
+	writeContent := func(name, content string) {
+		ExpectWithOffset(1, os.WriteFile(filepath.Join(contentDir, name), []byte(content), 0644)).To(Succeed())
+
+		manifest, err := os.OpenFile(filepath.Join(contentDir, ChecksumsFileName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		defer manifest.Close()
+		// The last checksum of a binary wins, like in a map.
+		_, err = fmt.Fprintf(manifest, "%s  %s\n", sha512Hex(content), name)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		hostRoot = GinkgoT().TempDir()
+		contentDir = GinkgoT().TempDir()
+
+		writeContent(RunscBinaryName, "runsc")
+		writeContent(ShimBinaryName, "shim")
+		writeHostFile(ContainerdConfigPath, "version = 2\n\n[plugins]\n")
+
+		opts = Options{
+			HostRoot:         hostRoot,
+			ContentDir:       contentDir,
+			BinDir:           "/var/bin/containerruntimes",
+			RunscConfigFlags: "net-raw = \"true\"\n",
+			Platform:         config.PlatformSystrap,
+		}
+
+		restarts = 0
+		kvm = false
+		DeferCleanup(func(restartContainerd func(context.Context, string) error, verifyContainerd func(context.Context, string, []string) error, kvmUsable func(string) bool, runscVersion func(context.Context, string) (string, error), startTestSandbox func(context.Context, string, string, config.Platform) error) {
+			RestartContainerd = restartContainerd
+			VerifyContainerd = verifyContainerd
+			KVMUsable = kvmUsable
+			RunscVersion = runscVersion
+			StartTestSandbox = startTestSandbox
+		}, RestartContainerd, VerifyContainerd, KVMUsable, RunscVersion, StartTestSandbox)
+		RestartContainerd = func(_ context.Context, root string) error {
+			Expect(root).To(Equal(hostRoot))
+			restarts++
+			return nil
+		}
+		verifiedRuntimes = nil
+		VerifyContainerd = func(_ context.Context, root string, runtimeNames []string) error {
+			Expect(root).To(Equal(hostRoot))
+			verifiedRuntimes = runtimeNames
+			return nil
+		}
+		KVMUsable = func(string) bool { return kvm }
+		RunscVersion = func(_ context.Context, path string) (string, error) {
+			Expect(path).To(Equal(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc")))
+			return "release-20250414.0", nil
+		}
+		smokeTests = 0
+		smokeTestFn = func() error { return nil }
+		StartTestSandbox = func(_ context.Context, root, runscPath string, _ config.Platform) error {
+			Expect(root).To(Equal(hostRoot))
+			Expect(runscPath).To(Equal("/var/bin/containerruntimes/runsc"))
+			smokeTests++
+			return smokeTestFn()
+		}
+	})
+
+	It("should install gVisor on a new node", func() {
+		result, err := New(log, opts).Install(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(&Result{
+			Platform:            config.PlatformSystrap,
+			RunscVersion:        "release-20250414.0",
+			ConfigHash:          sha256Hex("[runsc_config]\nnet-raw = \"true\"\n"),
+			ContainerdRestarted: true,
+			Changed: []string{
+				"/var/bin/containerruntimes/runsc",
+				"/var/bin/containerruntimes/containerd-shim-runsc-v1",
+				ContainerdConfigPath,
+				RunscConfigPath,
+			},
+		}))
+		Expect(restarts).To(Equal(1))
+		Expect(verifiedRuntimes).To(Equal([]string{"runsc"}))
+
+		Expect(readHostFile("/var/bin/containerruntimes/runsc")).To(Equal("runsc"))
+		Expect(readHostFile("/var/bin/containerruntimes/containerd-shim-runsc-v1")).To(Equal("shim"))
+		info, err := os.Stat(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+
+		Expect(readHostFile(ContainerdConfigPath)).To(Equal(`version = 2
+
+[plugins]
+  [plugins."io.containerd.grpc.v1.cri"]
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runsc]
+          runtime_type = "io.containerd.runsc.v1"
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runsc.options]
+            ConfigPath = "/etc/containerd/runsc.toml"
+            TypeUrl = "io.containerd.runsc.v1.options"
+`))
+		Expect(readHostFile(RunscConfigPath)).To(Equal("[runsc_config]\nnet-raw = \"true\"\n"))
+	})
+
+	It("should not restart containerd if nothing changed", func() {
+		_, err := New(log, opts).Install(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := New(log, opts).Install(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ContainerdRestarted).To(BeFalse())
+		Expect(result.Changed).To(BeEmpty())
+		Expect(restarts).To(Equal(1))
+	})
+
+	It("should update binaries without restarting containerd", func() {
+		_, err := New(log, opts).Install(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		writeContent(RunscBinaryName, "runsc-new")
+
+		result, err := New(log, opts).Install(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ContainerdRestarted).To(BeFalse())
+		Expect(result.Changed).To(ConsistOf("/var/bin/containerruntimes/runsc"))
+		Expect(readHostFile("/var/bin/containerruntimes/runsc")).To(Equal("runsc-new"))
+	})
+
+	It("should restart containerd if the runsc config changed", func() {
+		_, err := New(log, opts).Install(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		opts.RunscConfigFlags = "debug = \"true\""
+
+		result, err := New(log, opts).Install(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ContainerdRestarted).To(BeTrue())
+		Expect(restarts).To(Equal(2))
+		Expect(readHostFile(RunscConfigPath)).To(Equal("[runsc_config]\ndebug = \"true\"\n"))
+	})
+
+	It("should not change a containerd config which already contains the runsc runtime", func() {
+		containerdConfig := `version = 3
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc]
+runtime_type = "io.containerd.runsc.v1"
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc.options]
+TypeUrl = "io.containerd.runsc.v1.options"
+ConfigPath = "/etc/containerd/runsc.toml"
+`
+		writeHostFile(ContainerdConfigPath, containerdConfig)
+		writeHostFile(RunscConfigPath, "[runsc_config]\nnet-raw = \"true\"\n")
+
+		result, err := New(log, opts).Install(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ContainerdRestarted).To(BeFalse())
+		Expect(readHostFile(ContainerdConfigPath)).To(Equal(containerdConfig))
+	})
+
+	It("should update a runsc runtime with different options", func() {
+		writeHostFile(ContainerdConfigPath, `version = 3
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc]
+runtime_type = "io.containerd.runsc.v1"
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc.options]
+TypeUrl = "io.containerd.runsc.v1.options"
+ConfigPath = "/etc/runsc.toml"
+`)
+		writeHostFile(RunscConfigPath, "[runsc_config]\nnet-raw = \"true\"\n")
+
+		result, err := New(log, opts).Install(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ContainerdRestarted).To(BeTrue())
+		Expect(readHostFile(ContainerdConfigPath)).To(ContainSubstring(`ConfigPath = "/etc/containerd/runsc.toml"`))
+	})
+
+	It("should fail if the installed runsc binary cannot be executed", func() {
+		RunscVersion = func(context.Context, string) (string, error) {
+			return "", errors.New("exec format error")
+		}
+
+		_, err := New(log, opts).Install(ctx)
+		Expect(err).To(MatchError(ContainSubstring("failed determining runsc version: exec format error")))
+		Expect(restarts).To(BeZero())
+		Expect(readHostFile(ContainerdConfigPath)).To(Equal("version = 2\n\n[plugins]\n"))
+	})
+
+	Describe("rollback", func() {
+		BeforeEach(func() {
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(smokeTests).To(BeZero())
+
+			writeContent(RunscBinaryName, "runsc-new")
+			writeContent(ShimBinaryName, "shim-new")
+		})
+
+		It("should keep the previous binaries and smoke test the replaced binaries", func() {
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(smokeTests).To(Equal(1))
+
+			Expect(readHostFile("/var/bin/containerruntimes/runsc")).To(Equal("runsc-new"))
+			Expect(readHostFile("/var/bin/containerruntimes/runsc.previous")).To(Equal("runsc"))
+			Expect(readHostFile("/var/bin/containerruntimes/containerd-shim-runsc-v1")).To(Equal("shim-new"))
+			Expect(readHostFile("/var/bin/containerruntimes/containerd-shim-runsc-v1.previous")).To(Equal("shim"))
+		})
+
+		It("should roll back the binaries if the replaced runsc binary cannot be executed", func() {
+			RunscVersion = func(context.Context, string) (string, error) {
+				return "", errors.New("exec format error")
+			}
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).To(MatchError("failed determining runsc version: exec format error, rolled back to the previous binaries"))
+			Expect(smokeTests).To(BeZero())
+			Expect(restarts).To(Equal(1))
+
+			Expect(readHostFile("/var/bin/containerruntimes/runsc")).To(Equal("runsc"))
+			Expect(readHostFile("/var/bin/containerruntimes/containerd-shim-runsc-v1")).To(Equal("shim"))
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc.previous")).NotTo(BeAnExistingFile())
+		})
+
+		It("should roll back the binaries if the smoke test fails", func() {
+			smokeTestFn = func() error { return errors.New("sandbox failed") }
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).To(MatchError("failed starting smoke test sandbox with runsc release-20250414.0: sandbox failed, rolled back to the previous binaries"))
+			Expect(smokeTests).To(Equal(1))
+
+			Expect(readHostFile("/var/bin/containerruntimes/runsc")).To(Equal("runsc"))
+			Expect(readHostFile("/var/bin/containerruntimes/containerd-shim-runsc-v1")).To(Equal("shim"))
+		})
+
+		It("should roll back the binaries if an installed binary is corrupted while it is written", func() {
+			writeBinary := WriteBinary
+			DeferCleanup(test.WithVar(&WriteBinary, func(path string, content []byte, perm os.FileMode) error {
+				if filepath.Base(path) == ShimBinaryName {
+					content = []byte("corrupted")
+				}
+				return writeBinary(path, content, perm)
+			}))
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).To(MatchError(And(
+				ContainSubstring("verification of installed binary containerd-shim-runsc-v1 failed"),
+				ContainSubstring("rolled back to the previous binaries"),
+			)))
+			Expect(smokeTests).To(BeZero())
+
+			Expect(readHostFile("/var/bin/containerruntimes/runsc")).To(Equal("runsc"))
+			Expect(readHostFile("/var/bin/containerruntimes/containerd-shim-runsc-v1")).To(Equal("shim"))
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc.previous")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/containerd-shim-runsc-v1.previous")).NotTo(BeAnExistingFile())
+		})
+
+		It("should roll back the binaries if containerd cannot be verified after they have been replaced", func() {
+			VerifyContainerd = func(context.Context, string, []string) error {
+				return errors.New("runtime runsc is missing in the containerd config")
+			}
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).To(MatchError("failed verifying containerd: runtime runsc is missing in the containerd config, rolled back to the previous binaries"))
+			Expect(smokeTests).To(Equal(1))
+
+			Expect(readHostFile("/var/bin/containerruntimes/runsc")).To(Equal("runsc"))
+			Expect(readHostFile("/var/bin/containerruntimes/containerd-shim-runsc-v1")).To(Equal("shim"))
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc.previous")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/containerd-shim-runsc-v1.previous")).NotTo(BeAnExistingFile())
+			// The rolled back binaries keep the checksums of their release.
+			Expect(readHostFile(filepath.Join("/var/bin/containerruntimes", InstalledChecksumsFileName))).NotTo(ContainSubstring(sha512Hex("runsc-new")))
+		})
+
+		It("should roll back the binaries if the test sandbox fails after they have been replaced", func() {
+			opts.TestSandbox = true
+			// The first sandbox is the smoke test of the replaced binaries.
+			smokeTestFn = func() error {
+				if smokeTests > 1 {
+					return errors.New("sandbox failed")
+				}
+				return nil
+			}
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).To(MatchError("failed starting test sandbox: sandbox failed, rolled back to the previous binaries"))
+			Expect(smokeTests).To(Equal(2))
+
+			Expect(readHostFile("/var/bin/containerruntimes/runsc")).To(Equal("runsc"))
+			Expect(readHostFile("/var/bin/containerruntimes/containerd-shim-runsc-v1")).To(Equal("shim"))
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc.previous")).NotTo(BeAnExistingFile())
+		})
+
+		It("should only roll back the replaced binaries", func() {
+			writeContent(RunscBinaryName, "runsc")
+			smokeTestFn = func() error { return errors.New("sandbox failed") }
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).To(MatchError(ContainSubstring("rolled back to the previous binaries")))
+
+			Expect(readHostFile("/var/bin/containerruntimes/runsc")).To(Equal("runsc"))
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc.previous")).NotTo(BeAnExistingFile())
+			Expect(readHostFile("/var/bin/containerruntimes/containerd-shim-runsc-v1")).To(Equal("shim"))
+		})
+	})
+
+	Describe("checksums", func() {
+		It("should refuse to install if a shipped binary does not match the checksum manifest", func() {
+			Expect(os.WriteFile(filepath.Join(contentDir, ShimBinaryName), []byte("tampered"), 0644)).To(Succeed())
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).To(MatchError(And(
+				ContainSubstring("refusing to install, verification of shipped binary containerd-shim-runsc-v1 failed"),
+				ContainSubstring("does not match the expected checksum "+sha512Hex("shim")),
+			)))
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/containerd-shim-runsc-v1")).NotTo(BeAnExistingFile())
+		})
+
+		It("should refuse to install if the checksum manifest is missing", func() {
+			Expect(os.Remove(filepath.Join(contentDir, ChecksumsFileName))).To(Succeed())
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).To(MatchError(ContainSubstring("failed reading checksum manifest")))
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc")).NotTo(BeAnExistingFile())
+		})
+
+		It("should refuse to install if the checksum manifest does not contain a binary", func() {
+			Expect(os.WriteFile(filepath.Join(contentDir, ChecksumsFileName), []byte(sha512Hex("runsc")+"  runsc\n"), 0644)).To(Succeed())
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).To(MatchError(ContainSubstring("checksum manifest does not contain binary containerd-shim-runsc-v1")))
+		})
+
+		It("should refuse to install if the checksum manifest is invalid", func() {
+			Expect(os.WriteFile(filepath.Join(contentDir, ChecksumsFileName), []byte("1234  runsc\n"), 0644)).To(Succeed())
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).To(MatchError(ContainSubstring(`invalid line in checksum manifest: "1234  runsc"`)))
+		})
+
+		It("should accept binary mode entries in the checksum manifest", func() {
+			Expect(os.WriteFile(filepath.Join(contentDir, ChecksumsFileName), []byte(
+				sha512Hex("runsc")+" *runsc\n"+sha512Hex("shim")+" *containerd-shim-runsc-v1\n"), 0644)).To(Succeed())
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should report and replace installed binaries which have been modified on the node", func() {
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readHostFile("/var/bin/containerruntimes/runsc.SHA512SUMS")).To(ContainSubstring(sha512Hex("runsc") + "  runsc\n"))
+			writeHostFile("/var/bin/containerruntimes/runsc", "tampered")
+
+			reporter := &fakeTamperReporter{}
+			opts.TamperReporter = reporter
+			_, err = New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reporter.reported).To(Equal([][]string{{"/var/bin/containerruntimes/runsc"}}))
+			Expect(readHostFile("/var/bin/containerruntimes/runsc")).To(Equal("runsc"))
+
+			// The reinstalled binaries are not reported again.
+			_, err = New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reporter.reported).To(HaveLen(1))
+		})
+
+		It("should not report binaries of a previous release as modified", func() {
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			writeContent(RunscBinaryName, "runsc-new")
+
+			reporter := &fakeTamperReporter{}
+			opts.TamperReporter = reporter
+			_, err = New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reporter.reported).To(BeEmpty())
+			Expect(readHostFile("/var/bin/containerruntimes/runsc")).To(Equal("runsc-new"))
+			Expect(readHostFile("/var/bin/containerruntimes/runsc.SHA512SUMS")).To(ContainSubstring(sha512Hex("runsc-new") + "  runsc\n"))
+		})
+
+		It("should not reinstall modified binaries if they cannot be reported", func() {
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			writeHostFile("/var/bin/containerruntimes/containerd-shim-runsc-v1", "tampered")
+
+			opts.TamperReporter = &fakeTamperReporter{err: errors.New("forbidden")}
+			_, err = New(log, opts).Install(ctx)
+			Expect(err).To(MatchError("failed reporting modified binaries /var/bin/containerruntimes/containerd-shim-runsc-v1: forbidden"))
+			Expect(readHostFile("/var/bin/containerruntimes/containerd-shim-runsc-v1")).To(Equal("tampered"))
+		})
+	})
+
+	It("should fail if containerd does not serve the runsc runtime", func() {
+		VerifyContainerd = func(context.Context, string, []string) error {
+			return errors.New("runtime runsc is missing in the containerd config")
+		}
+
+		_, err := New(log, opts).Install(ctx)
+		Expect(err).To(MatchError(ContainSubstring("failed verifying containerd: runtime runsc is missing")))
+	})
+
+	Describe("test sandbox", func() {
+		var sandboxes int
+
+		BeforeEach(func() {
+			sandboxes = 0
+			opts.TestSandbox = true
+			StartTestSandbox = func(_ context.Context, root, runscPath string, platform config.Platform) error {
+				Expect(root).To(Equal(hostRoot))
+				Expect(runscPath).To(Equal("/var/bin/containerruntimes/runsc"))
+				Expect(platform).To(Equal(config.PlatformSystrap))
+				sandboxes++
+				return nil
+			}
+		})
+
+		It("should start a test sandbox after the installation", func() {
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sandboxes).To(Equal(1))
+		})
+
+		It("should fail if the test sandbox cannot be started", func() {
+			StartTestSandbox = func(context.Context, string, string, config.Platform) error {
+				return errors.New("sandbox failed")
+			}
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).To(MatchError(ContainSubstring("failed starting test sandbox: sandbox failed")))
+		})
+
+		It("should not start a test sandbox if not enabled", func() {
+			opts.TestSandbox = false
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sandboxes).To(BeZero())
+		})
+	})
+
+	It("should fail on an invalid containerd config", func() {
+		writeHostFile(ContainerdConfigPath, "version = 5\n")
+
+		_, err := New(log, opts).Install(ctx)
+		Expect(err).To(MatchError(ContainSubstring("unsupported containerd config version 5")))
+		Expect(restarts).To(BeZero())
+	})
+
+	Describe("containerd config modes", func() {
+		const dropInConfig = `version = 3
+
+[plugins]
+  [plugins."io.containerd.cri.v1.runtime"]
+    [plugins."io.containerd.cri.v1.runtime".containerd]
+      [plugins."io.containerd.cri.v1.runtime".containerd.runtimes]
+        [plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc]
+          runtime_type = "io.containerd.runsc.v1"
+          [plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc.options]
+            ConfigPath = "/etc/containerd/runsc.toml"
+            TypeUrl = "io.containerd.runsc.v1.options"
+`
+
+		It("should use a drop-in config if it is imported", func() {
+			containerdConfig := "imports = [\"/etc/containerd/conf.d/*.toml\"]\nversion = 3\n"
+			writeHostFile(ContainerdConfigPath, containerdConfig)
+
+			result, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ContainerdRestarted).To(BeTrue())
+			Expect(readHostFile(ContainerdConfigPath)).To(Equal(containerdConfig))
+			Expect(readHostFile(ContainerdDropInPath)).To(Equal(dropInConfig))
+
+			result, err = New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ContainerdRestarted).To(BeFalse())
+		})
+
+		It("should remove the runtime from the containerd config when switching to the drop-in config", func() {
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readHostFile(ContainerdConfigPath)).To(ContainSubstring("runsc"))
+
+			writeHostFile(ContainerdConfigPath, "imports = [\"conf.d/*.toml\"]\n"+readHostFile(ContainerdConfigPath))
+
+			result, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ContainerdRestarted).To(BeTrue())
+			Expect(readHostFile(ContainerdConfigPath)).NotTo(ContainSubstring("runsc"))
+			Expect(readHostFile(ContainerdDropInPath)).To(ContainSubstring(`[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runsc]`))
+		})
+
+		It("should fail in drop-in mode if the drop-in config is not imported", func() {
+			opts.ContainerdConfigMode = gvisor.ContainerdConfigModeDropIn
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).To(MatchError(ContainSubstring("does not import the drop-in config")))
+			Expect(filepath.Join(hostRoot, ContainerdDropInPath)).NotTo(BeAnExistingFile())
+		})
+
+		It("should remove the drop-in config in config-file mode", func() {
+			opts.ContainerdConfigMode = gvisor.ContainerdConfigModeConfigFile
+			writeHostFile(ContainerdConfigPath, "imports = [\"/etc/containerd/conf.d/*.toml\"]\nversion = 3\n")
+			writeHostFile(ContainerdDropInPath, dropInConfig)
+
+			result, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ContainerdRestarted).To(BeTrue())
+			Expect(filepath.Join(hostRoot, ContainerdDropInPath)).NotTo(BeAnExistingFile())
+			Expect(readHostFile(ContainerdConfigPath)).To(ContainSubstring(`[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc]`))
+		})
+	})
+
+	DescribeTable("auto platform",
+		func(kvmUsable bool, expectedPlatform config.Platform) {
+			kvm = kvmUsable
+			opts.Platform = config.PlatformAuto
+
+			result, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Platform).To(Equal(expectedPlatform))
+			Expect(readHostFile(RunscConfigPath)).To(Equal("[runsc_config]\nnet-raw = \"true\"\nplatform = \"" + string(expectedPlatform) + "\"\n"))
+		},
+		Entry("KVM is usable", true, config.PlatformKVM),
+		Entry("KVM is not usable", false, config.PlatformSystrap),
+	)
+
+	Describe("profiles", func() {
+		BeforeEach(func() {
+			opts.Profiles = map[string]string{
+				"gvisor-netraw": "net-raw = \"true\"\n",
+				"gvisor-debug":  "debug = \"true\"\n",
+			}
+		})
+
+		It("should configure a runtime with its own runsc config for each profile", func() {
+			result, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Profiles).To(Equal([]string{"gvisor-debug", "gvisor-netraw"}))
+			Expect(result.Changed).To(ContainElements(RunscConfigPath, "/etc/containerd/runsc-gvisor-debug.toml", "/etc/containerd/runsc-gvisor-netraw.toml"))
+			Expect(verifiedRuntimes).To(Equal([]string{"runsc", "runsc-gvisor-debug", "runsc-gvisor-netraw"}))
+
+			Expect(readHostFile("/etc/containerd/runsc-gvisor-debug.toml")).To(Equal("[runsc_config]\ndebug = \"true\"\n"))
+			Expect(readHostFile("/etc/containerd/runsc-gvisor-netraw.toml")).To(Equal("[runsc_config]\nnet-raw = \"true\"\n"))
+			Expect(readHostFile(ContainerdConfigPath)).To(ContainSubstring(`        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runsc-gvisor-debug]
+          runtime_type = "io.containerd.runsc.v1"
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runsc-gvisor-debug.options]
+            ConfigPath = "/etc/containerd/runsc-gvisor-debug.toml"
+            TypeUrl = "io.containerd.runsc.v1.options"
+`))
+
+			result, err = New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Changed).To(BeEmpty())
+		})
+
+		It("should add the platform to the runsc configs of the profiles in auto mode", func() {
+			kvm = true
+			opts.Platform = config.PlatformAuto
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readHostFile("/etc/containerd/runsc-gvisor-debug.toml")).To(Equal("[runsc_config]\ndebug = \"true\"\nplatform = \"kvm\"\n"))
+		})
+
+		It("should remove profiles which are no longer configured and keep other runtimes", func() {
+			writeHostFile(ContainerdConfigPath, `version = 2
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runsc-custom]
+runtime_type = "io.containerd.runc.v2"
+`)
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			delete(opts.Profiles, "gvisor-debug")
+
+			result, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ContainerdRestarted).To(BeTrue())
+			Expect(result.Changed).To(ConsistOf(ContainerdConfigPath, "/etc/containerd/runsc-gvisor-debug.toml"))
+			Expect(filepath.Join(hostRoot, "/etc/containerd/runsc-gvisor-debug.toml")).NotTo(BeAnExistingFile())
+			Expect(readHostFile(ContainerdConfigPath)).NotTo(ContainSubstring("runsc-gvisor-debug"))
+			Expect(readHostFile(ContainerdConfigPath)).To(ContainSubstring("runsc-gvisor-netraw"))
+			Expect(readHostFile(ContainerdConfigPath)).To(ContainSubstring("runsc-custom"))
+		})
+
+		It("should configure the runtimes of the profiles in the drop-in config", func() {
+			writeHostFile(ContainerdConfigPath, "imports = [\"/etc/containerd/conf.d/*.toml\"]\nversion = 3\n")
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readHostFile(ContainerdDropInPath)).To(And(
+				ContainSubstring(`[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc]`),
+				ContainSubstring(`[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc-gvisor-debug]`),
+				ContainSubstring(`[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runsc-gvisor-netraw]`),
+			))
+		})
+
+		It("should be removed on uninstallation", func() {
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = New(log, Options{HostRoot: hostRoot, BinDir: opts.BinDir}).Uninstall(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readHostFile(ContainerdConfigPath)).NotTo(ContainSubstring("runsc"))
+			Expect(filepath.Join(hostRoot, "/etc/containerd/runsc-gvisor-debug.toml")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(hostRoot, "/etc/containerd/runsc-gvisor-netraw.toml")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Describe("#Repairer", func() {
+		type report struct {
+			result *Result
+			err    error
+		}
+
+		var reports chan report
+
+		BeforeEach(func() {
+			DeferCleanup(test.WithVar(&RepairDebounce, 10*time.Millisecond))
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			reports = make(chan report, 10)
+		})
+
+		run := func(interval time.Duration) {
+			repairer, err := NewRepairer(New(log, opts))
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+			runCtx, cancel := context.WithCancel(ctx)
+			done := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+				repairer.Run(runCtx, interval, func(result *Result, err error) {
+					reports <- report{result, err}
+				})
+			}()
+			DeferCleanup(func() {
+				cancel()
+				Eventually(done).Should(BeClosed())
+			})
+		}
+
+		It("should restore a removed binary", func() {
+			run(time.Hour)
+
+			Expect(os.Remove(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc"))).To(Succeed())
+
+			var r report
+			Eventually(reports).Should(Receive(&r))
+			Expect(r.err).NotTo(HaveOccurred())
+			Expect(r.result.Changed).To(ConsistOf("/var/bin/containerruntimes/runsc"))
+			Expect(r.result.ContainerdRestarted).To(BeFalse())
+			Expect(readHostFile("/var/bin/containerruntimes/runsc")).To(Equal("runsc"))
+		})
+
+		It("should repair an overwritten containerd config and restart containerd", func() {
+			run(time.Hour)
+
+			writeHostFile(ContainerdConfigPath, "version = 2\n\n[plugins]\n")
+
+			var r report
+			Eventually(reports).Should(Receive(&r))
+			Expect(r.err).NotTo(HaveOccurred())
+			Expect(r.result.Changed).To(ConsistOf(ContainerdConfigPath))
+			Expect(r.result.ContainerdRestarted).To(BeTrue())
+			Expect(restarts).To(Equal(2))
+			Expect(readHostFile(ContainerdConfigPath)).To(ContainSubstring("runtimes.runsc"))
+		})
+
+		It("should ignore changes of other files", func() {
+			run(time.Hour)
+
+			writeHostFile("/etc/containerd/other.toml", "foo")
+
+			Consistently(reports, 100*time.Millisecond).ShouldNot(Receive())
+		})
+
+		It("should verify the installation periodically", func() {
+			run(10 * time.Millisecond)
+
+			var r report
+			Eventually(reports).Should(Receive(&r))
+			Expect(r.err).NotTo(HaveOccurred())
+			Expect(r.result.Changed).To(BeEmpty())
+		})
+
+		It("should report failures and start the test sandbox once the installation is repaired", func() {
+			var verifyErr atomic.Pointer[error]
+			verifyErr.Store(ptr.To(errors.New("containerd is not running")))
+			VerifyContainerd = func(context.Context, string, []string) error { return *verifyErr.Load() }
+			opts.TestSandbox = true
+			run(time.Hour)
+
+			runscConfig := readHostFile(RunscConfigPath)
+			writeHostFile(RunscConfigPath, "")
+
+			var r report
+			Eventually(reports).Should(Receive(&r))
+			Expect(r.err).To(MatchError(ContainSubstring("containerd is not running")))
+			Expect(smokeTests).To(BeZero())
+
+			verifyErr.Store(ptr.To[error](nil))
+			// Unchanged files are verified again with the test sandbox, as the previous repair failed. The file is replaced
+			// in one step, so that the repair never sees partially written content.
+			writeHostFile(RunscConfigPath+".new", runscConfig)
+			Expect(os.Rename(filepath.Join(hostRoot, RunscConfigPath+".new"), filepath.Join(hostRoot, RunscConfigPath))).To(Succeed())
+
+			// Repairs which have been triggered by the previous repair might still fail.
+			Eventually(func(g Gomega) {
+				g.Expect(reports).To(Receive(&r))
+				g.Expect(r.err).NotTo(HaveOccurred())
+			}).Should(Succeed())
+			Expect(r.result.Changed).To(BeEmpty())
+			Expect(smokeTests).To(Equal(1))
+		})
+	})
+
+	Describe("#OutdatedSandboxes", func() {
+		var (
+			procDir string
+			oldDir  string
+		)
+
+		// createProcess creates the proc files of a process which has been started from the binary at exe.
+		createProcess := func(pid, ppid int, name, exe string) {
+			dir := filepath.Join(procDir, fmt.Sprint(pid))
+			ExpectWithOffset(1, os.MkdirAll(dir, 0755)).To(Succeed())
+			ExpectWithOffset(1, os.WriteFile(filepath.Join(dir, "cmdline"), []byte(name+"\x00--foo\x00"), 0644)).To(Succeed())
+			ExpectWithOffset(1, os.WriteFile(filepath.Join(dir, "stat"), fmt.Appendf(nil, "%d (%s) S %d 1 1 0", pid, filepath.Base(name), ppid), 0644)).To(Succeed())
+			ExpectWithOffset(1, os.Symlink(exe, filepath.Join(dir, "exe"))).To(Succeed())
+		}
+
+		installed := func(name string) string {
+			return filepath.Join(hostRoot, "/var/bin/containerruntimes", name)
+		}
+
+		BeforeEach(func() {
+			procDir = GinkgoT().TempDir()
+			oldDir = GinkgoT().TempDir()
+			DeferCleanup(test.WithVar(&ProcPath, procDir))
+
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.WriteFile(filepath.Join(oldDir, RunscBinaryName), []byte("runsc"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(oldDir, ShimBinaryName), []byte("shim"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(procDir, "self"), 0755)).To(Succeed())
+		})
+
+		It("should count the sandboxes which use replaced binaries", func() {
+			// Up-to-date sandbox.
+			createProcess(10, 1, "/var/bin/containerruntimes/containerd-shim-runsc-v1", installed(ShimBinaryName))
+			createProcess(11, 10, "runsc-sandbox", installed(RunscBinaryName))
+			createProcess(12, 10, "runsc-gofer", installed(RunscBinaryName))
+			// Sandbox with an outdated shim.
+			createProcess(20, 1, "/var/bin/containerruntimes/containerd-shim-runsc-v1", filepath.Join(oldDir, ShimBinaryName))
+			createProcess(21, 20, "runsc-sandbox", installed(RunscBinaryName))
+			// Sandbox with outdated shim and runsc.
+			createProcess(30, 1, "/var/bin/containerruntimes/containerd-shim-runsc-v1", filepath.Join(oldDir, ShimBinaryName))
+			createProcess(31, 30, "runsc-sandbox", filepath.Join(oldDir, RunscBinaryName))
+			// Sandbox with outdated runsc.
+			createProcess(40, 1, "/var/bin/containerruntimes/containerd-shim-runsc-v1", installed(ShimBinaryName))
+			createProcess(41, 40, "runsc-sandbox", filepath.Join(oldDir, RunscBinaryName))
+			// Outdated sandbox whose shim is gone.
+			createProcess(51, 1, "runsc-sandbox", filepath.Join(oldDir, RunscBinaryName))
+			// Other processes.
+			createProcess(60, 1, "/usr/bin/containerd-shim-runc-v2", filepath.Join(oldDir, ShimBinaryName))
+
+			Expect(New(log, opts).OutdatedSandboxes()).To(Equal(4))
+		})
+
+		It("should skip processes which exited", func() {
+			Expect(os.MkdirAll(filepath.Join(procDir, "10"), 0755)).To(Succeed())
+			createProcess(20, 1, "/var/bin/containerruntimes/containerd-shim-runsc-v1", filepath.Join(oldDir, "missing"))
+
+			Expect(New(log, opts).OutdatedSandboxes()).To(BeZero())
+		})
+
+		It("should report the outdated sandboxes of the installation", func() {
+			// The link keeps the replaced binary like the kernel does for a running process.
+			Expect(os.Link(installed(ShimBinaryName), filepath.Join(oldDir, "running-shim"))).To(Succeed())
+			createProcess(10, 1, "/var/bin/containerruntimes/containerd-shim-runsc-v1", filepath.Join(oldDir, "running-shim"))
+			writeContent(ShimBinaryName, "shim-new")
+
+			result, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.OutdatedSandboxes).To(Equal(1))
+		})
+	})
+
+	Describe("#ConfigureRunsc", func() {
+		It("should only write the runsc config", func() {
+			kvm = true
+			opts.Platform = config.PlatformAuto
+
+			result, err := New(log, opts).ConfigureRunsc()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(&Result{
+				Platform:   config.PlatformKVM,
+				ConfigHash: sha256Hex("[runsc_config]\nnet-raw = \"true\"\nplatform = \"kvm\"\n"),
+			}))
+			Expect(restarts).To(BeZero())
+
+			Expect(readHostFile(RunscConfigPath)).To(Equal("[runsc_config]\nnet-raw = \"true\"\nplatform = \"kvm\"\n"))
+			Expect(readHostFile(ContainerdConfigPath)).To(Equal("version = 2\n\n[plugins]\n"))
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Describe("#Uninstall", func() {
+		It("should remove gVisor from the node", func() {
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := New(log, opts).Uninstall(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ContainerdRestarted).To(BeTrue())
+			Expect(restarts).To(Equal(2))
+
+			Expect(readHostFile(ContainerdConfigPath)).NotTo(ContainSubstring("runsc"))
+			Expect(filepath.Join(hostRoot, RunscConfigPath)).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/containerd-shim-runsc-v1")).NotTo(BeAnExistingFile())
+		})
+
+		It("should remove the previous binaries", func() {
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			writeContent(RunscBinaryName, "runsc-new")
+			_, err = New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = New(log, opts).Uninstall(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(hostRoot, "/var/bin/containerruntimes/runsc.previous")).NotTo(BeAnExistingFile())
+		})
+
+		It("should remove the drop-in config", func() {
+			writeHostFile(ContainerdConfigPath, "imports = [\"/etc/containerd/conf.d/*.toml\"]\nversion = 3\n")
+			_, err := New(log, opts).Install(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := New(log, opts).Uninstall(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ContainerdRestarted).To(BeTrue())
+			Expect(filepath.Join(hostRoot, ContainerdDropInPath)).NotTo(BeAnExistingFile())
+		})
+
+		It("should not restart containerd if gVisor is not installed", func() {
+			result, err := New(log, opts).Uninstall(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ContainerdRestarted).To(BeFalse())
+			Expect(restarts).To(BeZero())
+			Expect(readHostFile(ContainerdConfigPath)).To(Equal("version = 2\n\n[plugins]\n"))
+		})
+	})
+
+	Describe("#KVMUsable", func() {
+		It("should not consider KVM usable if the device does not exist", func() {
+			Expect(kvmUsable(hostRoot)).To(BeFalse())
+		})
+
+		It("should not consider KVM usable if the path is no character device", func() {
+			writeHostFile(KVMDevicePath, "")
+
+			Expect(kvmUsable(hostRoot)).To(BeFalse())
+		})
+	})
+
+	Describe("#RunscVersion", func() {
+		writeRunsc := func(script string) string {
+			path := filepath.Join(GinkgoT().TempDir(), RunscBinaryName)
+			ExpectWithOffset(1, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755)).To(Succeed())
+			return path
+		}
+
+		It("should return the version of runsc", func() {
+			path := writeRunsc("echo 'runsc version release-20250414.0'\necho 'spec: 1.1.0-rc.1'\n")
+
+			Expect(runscVersion(ctx, path)).To(Equal("release-20250414.0"))
+		})
+
+		It("should fail on unexpected output", func() {
+			path := writeRunsc("echo 'foo'\n")
+
+			_, err := runscVersion(ctx, path)
+			Expect(err).To(MatchError(ContainSubstring("unexpected output")))
+		})
+
+		It("should fail if runsc fails", func() {
+			path := writeRunsc("echo 'broken'\nexit 1\n")
+
+			_, err := runscVersion(ctx, path)
+			Expect(err).To(MatchError(ContainSubstring("broken")))
+		})
+	})
+
+	Describe("#PatchNode", func() {
+		It("should set and remove the labels and annotations of the node", func() {
+			c := fakeclient.NewClientBuilder().WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:        "node",
+				Labels:      map[string]string{"foo": "bar", "gvisor.runtime.extensions.gardener.cloud/platform": "kvm"},
+				Annotations: map[string]string{"foo": "bar"},
+			}}).Build()
+
+			Expect(PatchNode(ctx, c, "node",
+				map[string]*string{"gvisor.runtime.extensions.gardener.cloud/platform": nil, "missing": nil},
+				map[string]*string{"gvisor.runtime.extensions.gardener.cloud/runsc-version": ptr.To("release-20250414.0")},
+			)).To(Succeed())
+
+			node := &corev1.Node{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: "node"}, node)).To(Succeed())
+			Expect(node.Labels).To(Equal(map[string]string{"foo": "bar"}))
+			Expect(node.Annotations).To(Equal(map[string]string{
+				"foo": "bar",
+				"gvisor.runtime.extensions.gardener.cloud/runsc-version": "release-20250414.0",
+			}))
+		})
+	})
+
+	Describe("#ProfileLabels", func() {
+		It("should set the labels of the profiles and remove the labels of other profiles", func() {
+			c := fakeclient.NewClientBuilder().WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name: "node",
+				Labels: map[string]string{
+					"foo": "bar",
+					"profile.gvisor.runtime.extensions.gardener.cloud/gvisor-netraw": "true",
+					"profile.gvisor.runtime.extensions.gardener.cloud/gvisor-old":    "true",
+				},
+			}}).Build()
+
+			Expect(ProfileLabels(ctx, c, "node", []string{"gvisor-debug", "gvisor-netraw"})).To(Equal(map[string]*string{
+				"profile.gvisor.runtime.extensions.gardener.cloud/gvisor-debug":  ptr.To("true"),
+				"profile.gvisor.runtime.extensions.gardener.cloud/gvisor-netraw": ptr.To("true"),
+				"profile.gvisor.runtime.extensions.gardener.cloud/gvisor-old":    nil,
+			}))
+		})
+	})
+
+	Describe("#NodeTamperReporter", func() {
+		It("should annotate the node and create an event", func() {
+			c := fakeclient.NewClientBuilder().WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", UID: "1234"}}).Build()
+
+			Expect(NewNodeTamperReporter(c, "node").ReportTampered(ctx, []string{"/var/bin/containerruntimes/runsc", "/var/bin/containerruntimes/containerd-shim-runsc-v1"})).To(Succeed())
+
+			node := &corev1.Node{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: "node"}, node)).To(Succeed())
+			Expect(node.Annotations).To(HaveKeyWithValue("gvisor.runtime.extensions.gardener.cloud/tampered-binaries", "/var/bin/containerruntimes/runsc,/var/bin/containerruntimes/containerd-shim-runsc-v1"))
+
+			events := &corev1.EventList{}
+			Expect(c.List(ctx, events, client.InNamespace(metav1.NamespaceDefault))).To(Succeed())
+			Expect(events.Items).To(ConsistOf(gstruct.MatchFields(gstruct.IgnoreExtras, gstruct.Fields{
+				"InvolvedObject": Equal(corev1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: "node", UID: "1234"}),
+				"Reason":         Equal("GVisorBinariesTampered"),
+				"Type":           Equal(corev1.EventTypeWarning),
+				"Message":        Equal("Installed gVisor binaries do not match the checksums they have been installed with and are reinstalled: /var/bin/containerruntimes/runsc, /var/bin/containerruntimes/containerd-shim-runsc-v1"),
+			})))
+		})
+	})
+
+	Describe("#RemoveNodeTaint", func() {
+		It("should remove the taint from the node", func() {
+			c := fakeclient.NewClientBuilder().WithObjects(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node"},
+				Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+					{Key: "foo", Effect: corev1.TaintEffectNoExecute},
+					{Key: "gvisor.runtime.extensions.gardener.cloud/not-ready", Effect: corev1.TaintEffectNoSchedule},
+				}},
+			}).Build()
+
+			Expect(RemoveNodeTaint(ctx, c, "node", "gvisor.runtime.extensions.gardener.cloud/not-ready")).To(Succeed())
+
+			node := &corev1.Node{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: "node"}, node)).To(Succeed())
+			Expect(node.Spec.Taints).To(Equal([]corev1.Taint{{Key: "foo", Effect: corev1.TaintEffectNoExecute}}))
+		})
+
+		It("should not fail if the node does not have the taint", func() {
+			c := fakeclient.NewClientBuilder().WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}).Build()
+
+			Expect(RemoveNodeTaint(ctx, c, "node", "gvisor.runtime.extensions.gardener.cloud/not-ready")).To(Succeed())
+		})
+	})
+
+	Describe("#IncrementNodeAnnotation", func() {
+		It("should increment the counter in the annotation", func() {
+			c := fakeclient.NewClientBuilder().WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}).Build()
+
+			Expect(IncrementNodeAnnotation(ctx, c, "node", "gvisor.runtime.extensions.gardener.cloud/drift-count")).To(Equal(1))
+			Expect(IncrementNodeAnnotation(ctx, c, "node", "gvisor.runtime.extensions.gardener.cloud/drift-count")).To(Equal(2))
+
+			node := &corev1.Node{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: "node"}, node)).To(Succeed())
+			Expect(node.Annotations).To(HaveKeyWithValue("gvisor.runtime.extensions.gardener.cloud/drift-count", "2"))
+		})
+
+		It("should fail on an invalid counter", func() {
+			c := fakeclient.NewClientBuilder().WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:        "node",
+				Annotations: map[string]string{"gvisor.runtime.extensions.gardener.cloud/drift-count": "foo"},
+			}}).Build()
+
+			_, err := IncrementNodeAnnotation(ctx, c, "node", "gvisor.runtime.extensions.gardener.cloud/drift-count")
+			Expect(err).To(MatchError(ContainSubstring("invalid value of annotation")))
+		})
+	})
+})
+
+// fakeTamperReporter records the reported binaries.
+type fakeTamperReporter struct {
+	reported [][]string
+	err      error
+}
+
+func (r *fakeTamperReporter) ReportTampered(_ context.Context, paths []string) error {
+	r.reported = append(r.reported, paths)
+	return r.err
+}
@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinstaller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+)
+
+// PatchNode sets the given labels and annotations on the node with the given name. Labels and annotations with a nil
+// value are removed from the node.
+func PatchNode(ctx context.Context, c client.Client, nodeName string, labels, annotations map[string]*string) error {
+	// A JSON merge patch removes keys which are set to null, other labels and annotations of the node are kept.
+	metadata := map[string]any{}
+	if len(labels) > 0 {
+		metadata["labels"] = labels
+	}
+	if len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+
+	patch, err := json.Marshal(map[string]any{"metadata": metadata})
+	if err != nil {
+		return err
+	}
+
+	return c.Patch(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}, client.RawPatch(types.MergePatchType, patch))
+}
+
+// ProfileLabels returns the labels for the given profiles configured on the node with the given name, see
+// gvisor.LabelPrefixProfile. The labels of other profiles which are set on the node are returned with a nil value, so
+// that PatchNode removes them.
+func ProfileLabels(ctx context.Context, c client.Client, nodeName string, profiles []string) (map[string]*string, error) {
+	node := &corev1.Node{}
+	if err := c.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		return nil, err
+	}
+
+	labels := map[string]*string{}
+	for key := range node.Labels {
+		if strings.HasPrefix(key, gvisor.LabelPrefixProfile) {
+			labels[key] = nil
+		}
+	}
+	for _, profile := range profiles {
+		labels[gvisor.LabelPrefixProfile+profile] = ptr.To("true")
+	}
+	return labels, nil
+}
+
+// RemoveNodeTaint removes the taints with the given key from the node with the given name.
+func RemoveNodeTaint(ctx context.Context, c client.Client, nodeName, key string) error {
+	node := &corev1.Node{}
+	if err := c.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		return err
+	}
+
+	taints := slices.DeleteFunc(slices.Clone(node.Spec.Taints), func(taint corev1.Taint) bool {
+		return taint.Key == key
+	})
+	if len(taints) == len(node.Spec.Taints) {
+		return nil
+	}
+
+	// The optimistic lock prevents overwriting taints which have been changed concurrently.
+	patch := client.MergeFromWithOptions(node.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	node.Spec.Taints = taints
+	return c.Patch(ctx, node, patch)
+}
+
+// IncrementNodeAnnotation increments the counter in the annotation with the given key of the node with the given name.
+// A missing annotation is treated as zero. It returns the new value.
+func IncrementNodeAnnotation(ctx context.Context, c client.Client, nodeName, key string) (int, error) {
+	node := &corev1.Node{}
+	if err := c.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	if value, ok := node.Annotations[key]; ok {
+		var err error
+		if count, err = strconv.Atoi(value); err != nil {
+			return 0, fmt.Errorf("invalid value of annotation %s: %w", key, err)
+		}
+	}
+	count++
+
+	// The optimistic lock prevents losing increments of concurrent updates.
+	patch := client.MergeFromWithOptions(node.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	metav1.SetMetaDataAnnotation(&node.ObjectMeta, key, strconv.Itoa(count))
+	return count, c.Patch(ctx, node, patch)
+}
+
+// EventReasonTamperedBinaries is the reason of the events on nodes which report installed binaries which have been
+// modified on the node.
+const EventReasonTamperedBinaries = "GVisorBinariesTampered"
+
+// NodeTamperReporter reports installed binaries which have been modified on a node in the
+// gvisor.AnnotationTamperedBinaries annotation and as a warning event of the node.
+type NodeTamperReporter struct {
+	client   client.Client
+	nodeName string
+}
+
+var _ TamperReporter = (*NodeTamperReporter)(nil)
+
+// NewNodeTamperReporter creates a new NodeTamperReporter for the node with the given name.
+func NewNodeTamperReporter(c client.Client, nodeName string) *NodeTamperReporter {
+	return &NodeTamperReporter{client: c, nodeName: nodeName}
+}
+
+// ReportTampered sets the given paths in the annotation of the node and creates an event for the node.
+func (r *NodeTamperReporter) ReportTampered(ctx context.Context, paths []string) error {
+	node := &corev1.Node{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: r.nodeName}, node); err != nil {
+		return err
+	}
+
+	if err := PatchNode(ctx, r.client, r.nodeName, nil, map[string]*string{gvisor.AnnotationTamperedBinaries: ptr.To(strings.Join(paths, ","))}); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	// Events of nodes are created in the default namespace like the events of the kubelet.
+	return r.client.Create(ctx, &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: r.nodeName + ".", Namespace: metav1.NamespaceDefault},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Node",
+			Name:       r.nodeName,
+			UID:        node.UID,
+		},
+		Reason:         EventReasonTamperedBinaries,
+		Message:        fmt.Sprintf("Installed gVisor binaries do not match the checksums they have been installed with and are reinstalled: %s", strings.Join(paths, ", ")),
+		Type:           corev1.EventTypeWarning,
+		Source:         corev1.EventSource{Component: "gvisor-node-installer", Host: r.nodeName},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	})
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinstaller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// RepairDebounce is the duration the Repairer waits after a change of a watched file before the installation is
+// repaired, so that files which are written in several steps are only repaired once. Exposed for testing.
+var RepairDebounce = 5 * time.Second
+
+// Repairer repairs the installation of an Installer whenever one of the installed binaries or config files is changed on
+// the node and in a given interval. Changes of other processes, e.g. the OperatingSystemConfig reconciliation rewriting
+// the containerd config, are reverted by installing gVisor again, see Installer.Install.
+type Repairer struct {
+	installer    *Installer
+	watcher      *fsnotify.Watcher
+	watchedPaths map[string]bool
+}
+
+// NewRepairer creates a new Repairer for the installation of the given Installer and starts watching the installed
+// files. Changes are repaired once Run is called.
+func NewRepairer(installer *Installer) (*Repairer, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating file watcher: %w", err)
+	}
+
+	r := &Repairer{
+		installer:    installer,
+		watcher:      watcher,
+		watchedPaths: map[string]bool{},
+	}
+	for _, path := range installer.installedPaths() {
+		r.watchedPaths[installer.hostPath(path)] = true
+	}
+	r.watchDirs()
+	return r, nil
+}
+
+// Run repairs the installation until the context is cancelled. The result of each repair is passed to report, the
+// Changed paths of a successful repair are the drifted files. The test sandbox is only started if files have been
+// changed or the previous repair failed.
+func (r *Repairer) Run(ctx context.Context, interval time.Duration, report func(*Result, error)) {
+	defer func() {
+		if err := r.watcher.Close(); err != nil {
+			r.installer.log.Error(err, "Failed closing file watcher")
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	debounce := time.NewTimer(RepairDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	failed := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-r.watcher.Errors:
+			r.installer.log.Error(err, "Watching installed files failed")
+			continue
+		case event := <-r.watcher.Events:
+			if r.watchedPaths[event.Name] && !event.Has(fsnotify.Chmod) {
+				r.installer.log.Info("Installed file has been changed", "path", event.Name, "operation", event.Op.String())
+				debounce.Reset(RepairDebounce)
+			}
+			continue
+		case <-debounce.C:
+		case <-ticker.C:
+		}
+
+		result, err := r.installer.install(ctx, failed)
+		if ctx.Err() != nil {
+			return
+		}
+		failed = err != nil
+		if err != nil {
+			r.installer.log.Error(err, "Repairing installation failed")
+		} else if len(result.Changed) > 0 {
+			r.installer.log.Info("Repaired drifted installation", "changed", result.Changed)
+		}
+		report(result, err)
+
+		// Directories which have been removed and created again are watched again.
+		r.watchDirs()
+	}
+}
+
+// watchDirs adds the directories of the watched paths to the watcher. Files are not watched directly, as their watches
+// are lost when they are replaced or deleted. Missing directories are skipped.
+func (r *Repairer) watchDirs() {
+	var dirs []string
+	for path := range r.watchedPaths {
+		dirs = append(dirs, filepath.Dir(path))
+	}
+	slices.Sort(dirs)
+
+	for _, dir := range slices.Compact(dirs) {
+		if err := r.watcher.Add(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			r.installer.log.Error(err, "Failed watching directory", "dir", dir)
+		}
+	}
+}
+
+// installedPaths returns the paths of the binaries and config files on the node which are managed by the Installer.
+func (i *Installer) installedPaths() []string {
+	return append([]string{
+		filepath.Join(i.opts.BinDir, RunscBinaryName),
+		filepath.Join(i.opts.BinDir, ShimBinaryName),
+		ContainerdConfigPath,
+		ContainerdDropInPath,
+		RunscConfigPath,
+	}, i.profileRunscConfigPaths()...)
+}
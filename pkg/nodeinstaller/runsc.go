@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinstaller
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+)
+
+// writeRunscConfigs writes the runsc config and the runsc configs of the profiles and removes the runsc configs of
+// profiles which are no longer configured. It returns the paths of the changed configs and the hash of the runsc
+// config.
+func (i *Installer) writeRunscConfigs(platform config.Platform) ([]string, string, error) {
+	var changedPaths []string
+	changed, configHash, err := i.writeRunscConfig(RunscConfigPath, i.opts.RunscConfigFlags, platform)
+	if err != nil {
+		return nil, "", err
+	}
+	if changed {
+		changedPaths = append(changedPaths, RunscConfigPath)
+	}
+
+	for _, profile := range i.profiles() {
+		path := ProfileRunscConfigPath(profile)
+		changed, _, err := i.writeRunscConfig(path, i.opts.Profiles[profile], platform)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed writing runsc config of profile %s: %w", profile, err)
+		}
+		if changed {
+			changedPaths = append(changedPaths, path)
+		}
+	}
+
+	removedPaths, err := i.removeProfileRunscConfigs(i.profileRunscConfigPaths())
+	if err != nil {
+		return nil, "", err
+	}
+	return append(changedPaths, removedPaths...), configHash, nil
+}
+
+// writeRunscConfig writes the runsc config with the given flags to the given path. It returns true if the config was
+// changed and the hash of the config.
+func (i *Installer) writeRunscConfig(path, flags string, platform config.Platform) (bool, string, error) {
+	content := gvisor.RunscConfig(flags)
+	// The platform is not part of the rendered flags in auto mode as it is determined on the node.
+	if i.opts.Platform == config.PlatformAuto {
+		content = fmt.Appendf(content, "%s = %q\n", config.FlagPlatform, platform)
+	}
+
+	changed, err := writeFileIfChanged(i.hostPath(path), content, 0644)
+	if err != nil {
+		return false, "", err
+	}
+
+	if changed {
+		i.log.Info("Wrote runsc config", "path", path)
+	} else {
+		i.log.Info("Runsc config is up to date", "path", path)
+	}
+	return changed, gvisor.RunscConfigHash(content), nil
+}
+
+// profileRunscConfigPaths returns the paths of the runsc configs of the configured profiles.
+func (i *Installer) profileRunscConfigPaths() []string {
+	var paths []string
+	for _, profile := range i.profiles() {
+		paths = append(paths, ProfileRunscConfigPath(profile))
+	}
+	return paths
+}
+
+// removeProfileRunscConfigs removes the runsc configs of profiles from the node, except for the given paths. It returns
+// the paths of the removed configs.
+func (i *Installer) removeProfileRunscConfigs(keep []string) ([]string, error) {
+	matches, err := filepath.Glob(i.hostPath(profileRunscConfigPattern))
+	if err != nil {
+		return nil, err
+	}
+
+	var removedPaths []string
+	for _, match := range matches {
+		path, err := filepath.Rel(i.opts.HostRoot, match)
+		if err != nil {
+			return nil, err
+		}
+		path = "/" + path
+		if slices.Contains(keep, path) {
+			continue
+		}
+
+		removed, err := i.removeFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed removing runsc config %s: %w", path, err)
+		}
+		if removed {
+			i.log.Info("Removed runsc config of profile which is no longer configured", "path", path)
+			removedPaths = append(removedPaths, path)
+		}
+	}
+	return removedPaths, nil
+}
+
+// ConfigureRunsc only writes the runsc config with the platform determined on the node. It is used if the binaries and
+// the containerd config are provided by the OperatingSystemConfig of the worker pool. containerd is not restarted as
+// runsc reads its config whenever a sandbox is started. Profiles are not supported in this mode.
+func (i *Installer) ConfigureRunsc() (*Result, error) {
+	platform := i.platform()
+	_, configHash, err := i.writeRunscConfig(RunscConfigPath, i.opts.RunscConfigFlags, platform)
+	if err != nil {
+		return nil, fmt.Errorf("failed writing runsc config: %w", err)
+	}
+
+	return &Result{Platform: platform, ConfigHash: configHash}, nil
+}
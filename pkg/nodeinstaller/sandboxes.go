@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinstaller
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// runscSandboxProcessName is the name runsc uses as first argument of the sandbox processes it starts.
+const runscSandboxProcessName = "runsc-sandbox"
+
+// ProcPath is the path of the proc filesystem which contains the processes of the node. The installer runs in the PID
+// namespace of the node. Exposed for testing.
+var ProcPath = "/proc"
+
+// OutdatedSandboxes returns the number of running sandboxes whose shim or sandbox process has been started from a
+// binary which has been replaced since, e.g. by a new gVisor release. Running processes keep using the binary they have
+// been started from, i.e. such sandboxes only use the installed binaries once their pods are restarted.
+func (i *Installer) OutdatedSandboxes() (int, error) {
+	binDir := i.hostPath(i.opts.BinDir)
+	installedShim, err := os.Stat(filepath.Join(binDir, ShimBinaryName))
+	if err != nil {
+		return 0, err
+	}
+	installedRunsc, err := os.Stat(filepath.Join(binDir, RunscBinaryName))
+	if err != nil {
+		return 0, err
+	}
+
+	entries, err := os.ReadDir(ProcPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed reading processes: %w", err)
+	}
+
+	shims := map[int]bool{}
+	outdated := map[int]bool{}
+	var sandboxes []process
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		// Processes which exit while they are inspected are skipped.
+		p, ok := readProcess(pid)
+		if !ok {
+			continue
+		}
+
+		switch p.name {
+		case ShimBinaryName:
+			shims[pid] = true
+			if !os.SameFile(p.exe, installedShim) {
+				outdated[pid] = true
+			}
+		case runscSandboxProcessName:
+			if !os.SameFile(p.exe, installedRunsc) {
+				sandboxes = append(sandboxes, p)
+			}
+		}
+	}
+
+	// The shim of a pod is the subreaper of its sandbox process, i.e. both belong to the same sandbox.
+	for _, sandbox := range sandboxes {
+		if shims[sandbox.ppid] {
+			outdated[sandbox.ppid] = true
+		} else {
+			outdated[sandbox.pid] = true
+		}
+	}
+	return len(outdated), nil
+}
+
+// process is a running process of the node.
+type process struct {
+	pid  int
+	ppid int
+	// name is the base name of the first argument of the process.
+	name string
+	// exe is the binary the process has been started from. It is kept by the kernel if the binary is replaced.
+	exe os.FileInfo
+}
+
+func readProcess(pid int) (process, bool) {
+	dir := filepath.Join(ProcPath, strconv.Itoa(pid))
+	p := process{pid: pid}
+
+	cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")) // #nosec G304 -- path is not user provided.
+	if err != nil {
+		return p, false
+	}
+	name, _, _ := bytes.Cut(cmdline, []byte{0})
+	p.name = filepath.Base(string(name))
+	if p.name != ShimBinaryName && p.name != runscSandboxProcessName {
+		return p, false
+	}
+
+	// The process name in the stat file is enclosed in parentheses and may contain spaces, the parent PID is the second
+	// field after it.
+	stat, err := os.ReadFile(filepath.Join(dir, "stat")) // #nosec G304 -- path is not user provided.
+	if err != nil {
+		return p, false
+	}
+	end := strings.LastIndex(string(stat), ")")
+	if end < 0 {
+		return p, false
+	}
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 2 {
+		return p, false
+	}
+	if p.ppid, err = strconv.Atoi(fields[1]); err != nil {
+		return p, false
+	}
+
+	if p.exe, err = os.Stat(filepath.Join(dir, "exe")); err != nil {
+		return p, false
+	}
+	return p, true
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeinstaller
+
+import (
+	"context"
+	"fmt"
+	"path"
+)
+
+// Uninstall removes the runsc runtimes from the containerd config and the drop-in config, deletes the runsc configs and
+// the gVisor binaries and restarts containerd if its configuration was changed. Sandboxes which are still running keep
+// running, but can no longer be managed by containerd.
+func (i *Installer) Uninstall(ctx context.Context) (*Result, error) {
+	containerdConfig, err := i.readContainerdConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed reading containerd config: %w", err)
+	}
+
+	configChanged, err := removeRuntimes(containerdConfig, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed removing runsc runtimes from containerd config: %w", err)
+	}
+	if configChanged {
+		i.log.Info("Removing runsc runtimes from containerd config")
+		if err := i.writeContainerdConfig(containerdConfig); err != nil {
+			return nil, fmt.Errorf("failed writing containerd config: %w", err)
+		}
+	}
+
+	dropInRemoved, err := i.removeFile(ContainerdDropInPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed removing containerd drop-in config: %w", err)
+	}
+	if dropInRemoved {
+		i.log.Info("Removed containerd drop-in config", "path", ContainerdDropInPath)
+	}
+
+	for _, filePath := range []string{
+		RunscConfigPath,
+		path.Join(i.opts.BinDir, RunscBinaryName),
+		path.Join(i.opts.BinDir, RunscBinaryName+previousSuffix),
+		path.Join(i.opts.BinDir, ShimBinaryName),
+		path.Join(i.opts.BinDir, ShimBinaryName+previousSuffix),
+		path.Join(i.opts.BinDir, InstalledChecksumsFileName),
+	} {
+		removed, err := i.removeFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed removing %s: %w", filePath, err)
+		}
+		if removed {
+			i.log.Info("Removed file", "path", filePath)
+		}
+	}
+
+	if _, err := i.removeProfileRunscConfigs(nil); err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	if configChanged || dropInRemoved {
+		if err := i.restartContainerd(ctx); err != nil {
+			return nil, err
+		}
+		result.ContainerdRestarted = true
+	}
+
+	return result, nil
+}
@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	webhookcmd "github.com/gardener/gardener/extensions/pkg/webhook/cmd"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/webhook/operatingsystemconfig"
+)
+
+// WebhookSwitchOptions are the webhookcmd.SwitchOptions for the seed webhooks of the extension.
+func WebhookSwitchOptions() *webhookcmd.SwitchOptions {
+	return webhookcmd.NewSwitchOptions(
+		webhookcmd.Switch(operatingsystemconfig.Name, operatingsystemconfig.New),
+	)
+}
@@ -0,0 +1,285 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/component/extensions/operatingsystemconfig/original/components/kubelet"
+	oscutils "github.com/gardener/gardener/pkg/component/extensions/operatingsystemconfig/utils"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gvisorconfiguration "github.com/gardener/gardener-extension-runtime-gvisor/pkg/apis/config"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/charts"
+	gvisorcmd "github.com/gardener/gardener-extension-runtime-gvisor/pkg/cmd"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/nodeinstaller"
+)
+
+const (
+	// InstallerPath is the path of the gvisor-node-installer binary on the node.
+	InstallerPath = "/opt/bin/gvisor-node-installer"
+	// RunscConfigFlagsPath is the path of the file containing the rendered runsc config flags on the node.
+	RunscConfigFlagsPath = "/etc/gvisor-node-installer/runsc-config-flags"
+	// RunscConfigUnitName is the name of the systemd unit writing the runsc config on the node.
+	RunscConfigUnitName = "gvisor-runsc-config.service"
+
+	// contentDir is the directory of the gVisor binaries in the installation image.
+	contentDir = "/var/content"
+)
+
+var kubeletConfigCodec = kubelet.NewConfigCodec(oscutils.NewFileContentInlineCodec())
+
+// runscPluginPath is the path of the runsc runtime in the containerd plugins section. gardener-node-agent translates
+// it to the CRI plugin of the config version used on the node.
+var runscPluginPath = []string{"io.containerd.grpc.v1.cri", "containerd", "runtimes", nodeinstaller.RunscRuntimeName}
+
+type mutator struct {
+	client client.Client
+	config gvisorcmd.Config
+	clock  clock.Clock
+}
+
+// NewMutator returns a new instance of an OperatingSystemConfig mutator.
+func NewMutator(c client.Client, config gvisorcmd.Config, clock clock.Clock) extensionswebhook.Mutator {
+	return &mutator{
+		client: c,
+		config: config,
+		clock:  clock,
+	}
+}
+
+// Mutate adds the gVisor binaries, the runsc configuration and the runsc runtime in containerd to the
+// OperatingSystemConfig of worker pools which use gVisor, if gVisor is installed via the OperatingSystemConfig.
+// Otherwise, it adds the startup taint to the kubelet configuration of worker pools which enable it.
+func (m *mutator) Mutate(ctx context.Context, newObj, _ client.Object) error {
+	osc, ok := newObj.(*extensionsv1alpha1.OperatingSystemConfig)
+	if !ok {
+		return fmt.Errorf("wrong object type %T", newObj)
+	}
+
+	if osc.DeletionTimestamp != nil ||
+		osc.Spec.Purpose != extensionsv1alpha1.OperatingSystemConfigPurposeReconcile {
+		return nil
+	}
+
+	workerPoolName, ok := osc.Labels[v1beta1constants.LabelWorkerPool]
+	if !ok {
+		return nil
+	}
+
+	cluster, err := extensionscontroller.GetCluster(ctx, m.client, osc.Namespace)
+	if err != nil {
+		return fmt.Errorf("could not get cluster for namespace %q: %w", osc.Namespace, err)
+	}
+
+	containerRuntime := gvisorContainerRuntime(cluster, workerPoolName)
+	if containerRuntime == nil {
+		return nil
+	}
+
+	providerConfig, err := charts.DecodeProviderConfig(containerRuntime.ProviderConfig)
+	if err != nil {
+		return err
+	}
+
+	if m.config.InstallationMode == gvisor.InstallationModeOperatingSystemConfig {
+		if err := charts.ValidateInstallationMode(providerConfig, m.config.InstallationMode); err != nil {
+			return err
+		}
+		installation, err := m.installation(ctx, cluster, osc.Namespace, workerPoolName, providerConfig)
+		if err != nil {
+			return err
+		}
+		extensionswebhook.LogMutation(logger, osc.Kind, osc.Namespace, osc.Name)
+		return m.ensureGVisor(osc, installation)
+	}
+
+	if ptr.Deref(providerConfig.StartupTaint, false) {
+		extensionswebhook.LogMutation(logger, osc.Kind, osc.Namespace, osc.Name)
+		return ensureStartupTaint(osc)
+	}
+	return nil
+}
+
+// installation returns the Installation to add to the OperatingSystemConfig of the given worker pool. Like the
+// installation DaemonSet, disruptive changes are deferred until the maintenance time window of the shoot, i.e. the
+// Installation in the provider status of the ContainerRuntime of the worker pool is kept until then.
+func (m *mutator) installation(ctx context.Context, cluster *extensionscontroller.Cluster, namespace, workerPoolName string, providerConfig *gvisorconfiguration.GVisorConfiguration) (charts.Installation, error) {
+	containerRuntimeList := &extensionsv1alpha1.ContainerRuntimeList{}
+	if err := m.client.List(ctx, containerRuntimeList, client.InNamespace(namespace)); err != nil {
+		return charts.Installation{}, fmt.Errorf("could not list container runtimes in namespace %q: %w", namespace, err)
+	}
+
+	var current *charts.Installation
+	for _, cr := range containerRuntimeList.Items {
+		if cr.Spec.Type != gvisor.Type || cr.Spec.WorkerPool.Name != workerPoolName {
+			continue
+		}
+		var err error
+		if current, err = charts.CurrentInstallation(&cr); err != nil {
+			return charts.Installation{}, err
+		}
+	}
+
+	installation, _ := charts.InstallationToRollOut(current, charts.DesiredInstallation(providerConfig, m.config), providerConfig, cluster, m.clock)
+	return installation, nil
+}
+
+func (m *mutator) ensureGVisor(osc *extensionsv1alpha1.OperatingSystemConfig, installation charts.Installation) error {
+	var (
+		image = installation.Image
+		files = []extensionsv1alpha1.File{
+			imageFile(image, path.Join(contentDir, nodeinstaller.RunscBinaryName), path.Join(extensionsv1alpha1.ContainerDRuntimeContainersBinFolder, nodeinstaller.RunscBinaryName)),
+			imageFile(image, path.Join(contentDir, nodeinstaller.ShimBinaryName), path.Join(extensionsv1alpha1.ContainerDRuntimeContainersBinFolder, nodeinstaller.ShimBinaryName)),
+			imageFile(image, "/gvisor-node-installer", InstallerPath),
+			{
+				Path:        RunscConfigFlagsPath,
+				Permissions: ptr.To[uint32](0644),
+				Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{
+					Data: installation.RenderRunscConfigFlags(),
+				}},
+			},
+		}
+	)
+
+	for _, file := range files {
+		osc.Spec.Files = extensionswebhook.EnsureFileWithPath(osc.Spec.Files, file)
+	}
+
+	osc.Spec.Units = extensionswebhook.EnsureUnitWithName(osc.Spec.Units, extensionsv1alpha1.Unit{
+		Name:      RunscConfigUnitName,
+		Command:   ptr.To(extensionsv1alpha1.CommandStart),
+		Enable:    ptr.To(true),
+		Content:   ptr.To(runscConfigUnitContent(installation.Platform)),
+		FilePaths: []string{InstallerPath, RunscConfigFlagsPath},
+	})
+
+	return ensureRunscPlugin(osc)
+}
+
+// ensureRunscPlugin configures the runsc runtime in the containerd config written by gardener-node-agent.
+func ensureRunscPlugin(osc *extensionsv1alpha1.OperatingSystemConfig) error {
+	values, err := json.Marshal(map[string]any{
+		"runtime_type": nodeinstaller.RunscRuntime.Type,
+		"options":      nodeinstaller.RunscRuntime.Options,
+	})
+	if err != nil {
+		return fmt.Errorf("could not marshal runsc runtime: %w", err)
+	}
+
+	if osc.Spec.CRIConfig == nil {
+		osc.Spec.CRIConfig = &extensionsv1alpha1.CRIConfig{Name: extensionsv1alpha1.CRINameContainerD}
+	}
+	if osc.Spec.CRIConfig.Containerd == nil {
+		osc.Spec.CRIConfig.Containerd = &extensionsv1alpha1.ContainerdConfig{}
+	}
+
+	plugin := extensionsv1alpha1.PluginConfig{Path: runscPluginPath, Values: &apiextensionsv1.JSON{Raw: values}}
+	for i, p := range osc.Spec.CRIConfig.Containerd.Plugins {
+		if strings.Join(p.Path, ".") == strings.Join(runscPluginPath, ".") {
+			osc.Spec.CRIConfig.Containerd.Plugins[i] = plugin
+			return nil
+		}
+	}
+	osc.Spec.CRIConfig.Containerd.Plugins = append(osc.Spec.CRIConfig.Containerd.Plugins, plugin)
+	return nil
+}
+
+// ensureStartupTaint adds the startup taint to the taints the kubelet registers the node with. The taint is removed by
+// the installation DaemonSet once gVisor has been installed on the node. Existing nodes are not tainted.
+func ensureStartupTaint(osc *extensionsv1alpha1.OperatingSystemConfig) error {
+	for i, file := range osc.Spec.Files {
+		if file.Path != v1beta1constants.OperatingSystemConfigFilePathKubeletConfig || file.Content.Inline == nil {
+			continue
+		}
+
+		kubeletConfig, err := kubeletConfigCodec.Decode(file.Content.Inline)
+		if err != nil {
+			return fmt.Errorf("could not decode kubelet config: %w", err)
+		}
+
+		if slices.ContainsFunc(kubeletConfig.RegisterWithTaints, func(taint corev1.Taint) bool {
+			return taint.Key == gvisor.TaintKeyNotReady
+		}) {
+			return nil
+		}
+		kubeletConfig.RegisterWithTaints = append(kubeletConfig.RegisterWithTaints, corev1.Taint{
+			Key:    gvisor.TaintKeyNotReady,
+			Effect: corev1.TaintEffectNoSchedule,
+		})
+
+		content, err := kubeletConfigCodec.Encode(kubeletConfig, file.Content.Inline.Encoding)
+		if err != nil {
+			return fmt.Errorf("could not encode kubelet config: %w", err)
+		}
+		osc.Spec.Files[i].Content.Inline = content
+		return nil
+	}
+
+	return fmt.Errorf("kubelet config %s not found in OperatingSystemConfig", v1beta1constants.OperatingSystemConfigFilePathKubeletConfig)
+}
+
+// gvisorContainerRuntime returns the gVisor container runtime of the worker pool with the given name or nil if the
+// worker pool does not use gVisor.
+func gvisorContainerRuntime(cluster *extensionscontroller.Cluster, workerPoolName string) *gardencorev1beta1.ContainerRuntime {
+	if cluster.Shoot == nil {
+		return nil
+	}
+
+	for _, worker := range cluster.Shoot.Spec.Provider.Workers {
+		if worker.Name != workerPoolName || worker.CRI == nil {
+			continue
+		}
+		for _, containerRuntime := range worker.CRI.ContainerRuntimes {
+			if containerRuntime.Type == gvisor.Type {
+				return &containerRuntime
+			}
+		}
+	}
+	return nil
+}
+
+func imageFile(image, pathInImage, path string) extensionsv1alpha1.File {
+	return extensionsv1alpha1.File{
+		Path:        path,
+		Permissions: ptr.To[uint32](0755),
+		Content: extensionsv1alpha1.FileContent{ImageRef: &extensionsv1alpha1.FileContentImageRef{
+			Image:           image,
+			FilePathInImage: pathInImage,
+		}},
+	}
+}
+
+// runscConfigUnitContent returns the systemd unit which writes the runsc config before containerd is started. The
+// platform is determined on the node in `auto` mode.
+func runscConfigUnitContent(platform gvisorconfiguration.Platform) string {
+	return `[Unit]
+Description=Write the runsc config of gVisor
+Before=containerd.service
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=` + InstallerPath + ` --host-root=/ --runsc-config-only --runsc-config-flags-file=` + RunscConfigFlagsPath + ` --platform=` + string(platform) + `
+
+[Install]
+WantedBy=multi-user.target
+`
+}
@@ -0,0 +1,337 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	"github.com/gardener/gardener/pkg/component/extensions/operatingsystemconfig/original/components/kubelet"
+	oscutils "github.com/gardener/gardener/pkg/component/extensions/operatingsystemconfig/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+	testclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/gardener/gardener-extension-runtime-gvisor/imagevector"
+	gvisorcmd "github.com/gardener/gardener-extension-runtime-gvisor/pkg/cmd"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+	. "github.com/gardener/gardener-extension-runtime-gvisor/pkg/webhook/operatingsystemconfig"
+)
+
+var _ = Describe("Mutator", func() {
+	const namespace = "shoot--foo--bar"
+
+	var (
+		ctx = context.Background()
+
+		c         client.Client
+		config    gvisorcmd.Config
+		fakeClock *testclock.FakeClock
+		mutator   extensionswebhook.Mutator
+
+		shoot *gardencorev1beta1.Shoot
+		osc   *extensionsv1alpha1.OperatingSystemConfig
+
+		image = imagevector.FindImage(gvisor.RuntimeGVisorInstallationImageName, "")
+	)
+
+	createCluster := func() {
+		shootJSON, err := json.Marshal(shoot)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		ExpectWithOffset(1, c.Create(ctx, &extensionsv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+			Spec: extensionsv1alpha1.ClusterSpec{
+				CloudProfile: runtime.RawExtension{Raw: []byte("{}")},
+				Seed:         &runtime.RawExtension{Raw: []byte("{}")},
+				Shoot:        runtime.RawExtension{Raw: shootJSON},
+			},
+		})).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		c = fakeclient.NewClientBuilder().WithScheme(kubernetes.SeedScheme).Build()
+		config = gvisorcmd.Config{InstallationMode: gvisor.InstallationModeOperatingSystemConfig}
+		fakeClock = testclock.NewFakeClock(time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+		mutator = NewMutator(c, config, fakeClock)
+
+		shoot = &gardencorev1beta1.Shoot{
+			Spec: gardencorev1beta1.ShootSpec{
+				Provider: gardencorev1beta1.Provider{
+					Workers: []gardencorev1beta1.Worker{
+						{Name: "worker-default"},
+						{
+							Name: "worker-gvisor",
+							CRI: &gardencorev1beta1.CRI{
+								Name: gardencorev1beta1.CRINameContainerD,
+								ContainerRuntimes: []gardencorev1beta1.ContainerRuntime{{
+									Type:           "gvisor",
+									ProviderConfig: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","netRaw":true,"nvproxy":true,"platform":"auto"}`)},
+								}},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		osc = &extensionsv1alpha1.OperatingSystemConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osc",
+				Namespace: namespace,
+				Labels:    map[string]string{"worker.gardener.cloud/pool": "worker-gvisor"},
+			},
+			Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+				Purpose:   extensionsv1alpha1.OperatingSystemConfigPurposeReconcile,
+				CRIConfig: &extensionsv1alpha1.CRIConfig{Name: extensionsv1alpha1.CRINameContainerD},
+				Files:     []extensionsv1alpha1.File{{Path: "/etc/foo"}},
+			},
+		}
+	})
+
+	It("should return an error for unexpected object types", func() {
+		Expect(mutator.Mutate(ctx, &corev1.Pod{}, nil)).To(MatchError(ContainSubstring("wrong object type")))
+	})
+
+	It("should add gVisor to the OperatingSystemConfig of a worker pool using gVisor", func() {
+		createCluster()
+
+		Expect(mutator.Mutate(ctx, osc, nil)).To(Succeed())
+
+		Expect(osc.Spec.Files).To(ConsistOf(
+			extensionsv1alpha1.File{Path: "/etc/foo"},
+			extensionsv1alpha1.File{
+				Path:        "/var/bin/containerruntimes/runsc",
+				Permissions: ptr.To[uint32](0755),
+				Content:     extensionsv1alpha1.FileContent{ImageRef: &extensionsv1alpha1.FileContentImageRef{Image: image, FilePathInImage: "/var/content/runsc"}},
+			},
+			extensionsv1alpha1.File{
+				Path:        "/var/bin/containerruntimes/containerd-shim-runsc-v1",
+				Permissions: ptr.To[uint32](0755),
+				Content:     extensionsv1alpha1.FileContent{ImageRef: &extensionsv1alpha1.FileContentImageRef{Image: image, FilePathInImage: "/var/content/containerd-shim-runsc-v1"}},
+			},
+			extensionsv1alpha1.File{
+				Path:        InstallerPath,
+				Permissions: ptr.To[uint32](0755),
+				Content:     extensionsv1alpha1.FileContent{ImageRef: &extensionsv1alpha1.FileContentImageRef{Image: image, FilePathInImage: "/gvisor-node-installer"}},
+			},
+			extensionsv1alpha1.File{
+				Path:        RunscConfigFlagsPath,
+				Permissions: ptr.To[uint32](0644),
+				Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: "net-raw = \"true\"\nnvproxy = \"true\"\n"}},
+			},
+		))
+
+		Expect(osc.Spec.Units).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Name":      Equal(RunscConfigUnitName),
+			"Command":   Equal(ptr.To(extensionsv1alpha1.CommandStart)),
+			"Enable":    Equal(ptr.To(true)),
+			"Content":   PointTo(ContainSubstring("ExecStart=/opt/bin/gvisor-node-installer --host-root=/ --runsc-config-only --runsc-config-flags-file=/etc/gvisor-node-installer/runsc-config-flags --platform=auto\n")),
+			"FilePaths": ConsistOf(InstallerPath, RunscConfigFlagsPath),
+		})))
+
+		Expect(osc.Spec.CRIConfig.Containerd.Plugins).To(ConsistOf(extensionsv1alpha1.PluginConfig{
+			Path:   []string{"io.containerd.grpc.v1.cri", "containerd", "runtimes", "runsc"},
+			Values: &apiextensionsv1.JSON{Raw: []byte(`{"options":{"ConfigPath":"/etc/containerd/runsc.toml","TypeUrl":"io.containerd.runsc.v1.options"},"runtime_type":"io.containerd.runsc.v1"}`)},
+		}))
+	})
+
+	It("should not add gVisor twice", func() {
+		createCluster()
+
+		Expect(mutator.Mutate(ctx, osc, nil)).To(Succeed())
+		expected := osc.DeepCopy()
+
+		Expect(mutator.Mutate(ctx, osc, nil)).To(Succeed())
+		Expect(osc).To(Equal(expected))
+	})
+
+	Describe("maintenance time window", func() {
+		const installedImage = "example.com/gvisor-installation:installed"
+
+		runscConfigFlagsFile := func() extensionsv1alpha1.File {
+			idx := slices.IndexFunc(osc.Spec.Files, func(file extensionsv1alpha1.File) bool { return file.Path == RunscConfigFlagsPath })
+			ExpectWithOffset(1, idx).NotTo(Equal(-1))
+			return osc.Spec.Files[idx]
+		}
+
+		BeforeEach(func() {
+			shoot.Spec.Maintenance = &gardencorev1beta1.Maintenance{
+				TimeWindow: &gardencorev1beta1.MaintenanceTimeWindow{Begin: "220000+0000", End: "230000+0000"},
+			}
+			createCluster()
+
+			Expect(c.Create(ctx, &extensionsv1alpha1.ContainerRuntime{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-gvisor-gvisor", Namespace: namespace},
+				Spec: extensionsv1alpha1.ContainerRuntimeSpec{
+					BinaryPath:  extensionsv1alpha1.ContainerDRuntimeContainersBinFolder,
+					DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: "gvisor"},
+					WorkerPool:  extensionsv1alpha1.ContainerRuntimeWorkerPool{Name: "worker-gvisor"},
+				},
+				Status: extensionsv1alpha1.ContainerRuntimeStatus{DefaultStatus: extensionsv1alpha1.DefaultStatus{
+					ProviderStatus: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorStatus","installationImage":"` + installedImage + `","platform":"systrap","runscFlags":{"net-raw":"false"}}`)},
+				}},
+			})).To(Succeed())
+		})
+
+		It("should keep the installation of the ContainerRuntime outside of the maintenance time window", func() {
+			Expect(mutator.Mutate(ctx, osc, nil)).To(Succeed())
+
+			Expect(osc.Spec.Files).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Path":    Equal("/var/bin/containerruntimes/runsc"),
+				"Content": Equal(extensionsv1alpha1.FileContent{ImageRef: &extensionsv1alpha1.FileContentImageRef{Image: installedImage, FilePathInImage: "/var/content/runsc"}}),
+			})))
+			Expect(runscConfigFlagsFile().Content.Inline.Data).To(Equal("net-raw = \"false\"\n"))
+			Expect(osc.Spec.Units).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Content": PointTo(ContainSubstring("--platform=systrap\n")),
+			})))
+		})
+
+		It("should roll out the desired installation in the maintenance time window", func() {
+			fakeClock.SetTime(time.Date(2026, 10, 18, 22, 30, 0, 0, time.UTC))
+
+			Expect(mutator.Mutate(ctx, osc, nil)).To(Succeed())
+
+			Expect(osc.Spec.Files).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Path":    Equal("/var/bin/containerruntimes/runsc"),
+				"Content": Equal(extensionsv1alpha1.FileContent{ImageRef: &extensionsv1alpha1.FileContentImageRef{Image: image, FilePathInImage: "/var/content/runsc"}}),
+			})))
+			Expect(runscConfigFlagsFile().Content.Inline.Data).To(Equal("net-raw = \"true\"\nnvproxy = \"true\"\n"))
+			Expect(osc.Spec.Units).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Content": PointTo(ContainSubstring("--platform=auto\n")),
+			})))
+		})
+	})
+
+	It("should use the test image if configured", func() {
+		shoot.Spec.Provider.Workers[1].CRI.ContainerRuntimes[0].ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","testImageTag":"test"}`)}
+		createCluster()
+		config.InstallationTestRepository = ptr.To("foo.bar/gvisor-installation")
+		mutator = NewMutator(c, config, fakeClock)
+
+		Expect(mutator.Mutate(ctx, osc, nil)).To(Succeed())
+		Expect(osc.Spec.Files).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Path":    Equal("/var/bin/containerruntimes/runsc"),
+			"Content": Equal(extensionsv1alpha1.FileContent{ImageRef: &extensionsv1alpha1.FileContentImageRef{Image: "foo.bar/gvisor-installation:test", FilePathInImage: "/var/content/runsc"}}),
+		})))
+	})
+
+	It("should fail with a configuration problem if the provider config is invalid", func() {
+		shoot.Spec.Provider.Workers[1].CRI.ContainerRuntimes[0].ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","platform":"xen"}`)}
+		createCluster()
+
+		Expect(mutator.Mutate(ctx, osc, nil)).To(MatchError(ContainSubstring("providerConfig.platform")))
+	})
+
+	DescribeTable("should fail with a configuration problem if the provider config is not supported in operatingsystemconfig installation mode",
+		func(providerConfig, fieldPath string) {
+			shoot.Spec.Provider.Workers[1].CRI.ContainerRuntimes[0].ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration",` + providerConfig + `}`)}
+			createCluster()
+			expected := osc.DeepCopy()
+
+			Expect(mutator.Mutate(ctx, osc, nil)).To(MatchError(ContainSubstring(fieldPath + ": Forbidden: not supported if gVisor is installed via the OperatingSystemConfig")))
+			Expect(osc).To(Equal(expected))
+		},
+		Entry("startup taint", `"startupTaint":true`, "providerConfig.startupTaint"),
+		Entry("rollout", `"rollout":{"maxUnavailable":1}`, "providerConfig.rollout"),
+		Entry("drain", `"rollout":{"drain":true}`, "providerConfig.rollout"),
+		Entry("profiles", `"profiles":[{"name":"gvisor-netraw","netRaw":true}]`, "providerConfig.profiles"),
+	)
+
+	DescribeTable("should not mutate the OperatingSystemConfig",
+		func(mutate func()) {
+			createCluster()
+			mutate()
+			expected := osc.DeepCopy()
+
+			Expect(NewMutator(c, config, fakeClock).Mutate(ctx, osc, nil)).To(Succeed())
+			Expect(osc).To(Equal(expected))
+		},
+		Entry("in daemonset installation mode", func() { config.InstallationMode = gvisor.InstallationModeDaemonSet }),
+		Entry("with provision purpose", func() { osc.Spec.Purpose = extensionsv1alpha1.OperatingSystemConfigPurposeProvision }),
+		Entry("without worker pool label", func() { osc.Labels = nil }),
+		Entry("of a worker pool without gVisor", func() { osc.Labels["worker.gardener.cloud/pool"] = "worker-default" }),
+		Entry("of an unknown worker pool", func() { osc.Labels["worker.gardener.cloud/pool"] = "worker-unknown" }),
+		Entry("in deletion", func() { osc.DeletionTimestamp = &metav1.Time{} }),
+	)
+
+	Describe("startup taint", func() {
+		var kubeletConfigCodec = kubelet.NewConfigCodec(oscutils.NewFileContentInlineCodec())
+
+		kubeletConfigFile := func(kubeletConfig *kubeletconfigv1beta1.KubeletConfiguration) extensionsv1alpha1.File {
+			content, err := kubeletConfigCodec.Encode(kubeletConfig, "b64")
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+			return extensionsv1alpha1.File{
+				Path:    "/var/lib/kubelet/config/kubelet",
+				Content: extensionsv1alpha1.FileContent{Inline: content},
+			}
+		}
+
+		BeforeEach(func() {
+			config.InstallationMode = gvisor.InstallationModeDaemonSet
+			mutator = NewMutator(c, config, fakeClock)
+
+			shoot.Spec.Provider.Workers[1].CRI.ContainerRuntimes[0].ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"gvisor.runtime.extensions.config.gardener.cloud/v1beta1","kind":"GVisorConfiguration","startupTaint":true}`)}
+			osc.Spec.Files = append(osc.Spec.Files, kubeletConfigFile(&kubeletconfigv1beta1.KubeletConfiguration{
+				RegisterWithTaints: []corev1.Taint{{Key: "foo", Effect: corev1.TaintEffectNoExecute}},
+			}))
+		})
+
+		It("should add the startup taint to the kubelet config", func() {
+			createCluster()
+
+			Expect(mutator.Mutate(ctx, osc, nil)).To(Succeed())
+
+			Expect(osc.Spec.Files).To(HaveLen(2))
+			Expect(osc.Spec.Files[1].Content.Inline.Encoding).To(Equal("b64"))
+			kubeletConfig, err := kubeletConfigCodec.Decode(osc.Spec.Files[1].Content.Inline)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kubeletConfig.RegisterWithTaints).To(Equal([]corev1.Taint{
+				{Key: "foo", Effect: corev1.TaintEffectNoExecute},
+				{Key: "gvisor.runtime.extensions.gardener.cloud/not-ready", Effect: corev1.TaintEffectNoSchedule},
+			}))
+		})
+
+		It("should not add the startup taint twice", func() {
+			createCluster()
+
+			Expect(mutator.Mutate(ctx, osc, nil)).To(Succeed())
+			expected := osc.DeepCopy()
+
+			Expect(mutator.Mutate(ctx, osc, nil)).To(Succeed())
+			Expect(osc).To(Equal(expected))
+		})
+
+		It("should not add the startup taint if it is not enabled", func() {
+			shoot.Spec.Provider.Workers[1].CRI.ContainerRuntimes[0].ProviderConfig = nil
+			createCluster()
+			expected := osc.DeepCopy()
+
+			Expect(mutator.Mutate(ctx, osc, nil)).To(Succeed())
+			Expect(osc).To(Equal(expected))
+		})
+
+		It("should fail if the kubelet config is missing", func() {
+			osc.Spec.Files = osc.Spec.Files[:1]
+			createCluster()
+
+			Expect(mutator.Mutate(ctx, osc, nil)).To(MatchError(ContainSubstring("kubelet config /var/lib/kubelet/config/kubelet not found")))
+		})
+	})
+})
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	gvisorcmd "github.com/gardener/gardener-extension-runtime-gvisor/pkg/cmd"
+	"github.com/gardener/gardener-extension-runtime-gvisor/pkg/gvisor"
+)
+
+const (
+	// Name is a name for the OperatingSystemConfig mutation webhook.
+	Name = "operatingsystemconfig"
+)
+
+var logger = log.Log.WithName("gvisor-operatingsystemconfig-webhook")
+
+// AddOptions are options to apply when adding the webhook to the manager.
+type AddOptions struct {
+	// Config contains configuration for the gVisor installation.
+	Config gvisorcmd.Config
+}
+
+// DefaultAddOptions are the default AddOptions for New.
+var DefaultAddOptions = AddOptions{}
+
+// New creates a new webhook that mutates OperatingSystemConfig resources in the seed.
+func New(mgr manager.Manager) (*extensionswebhook.Webhook, error) {
+	logger.Info("Setting up webhook", "name", Name)
+
+	return extensionswebhook.New(mgr, extensionswebhook.Args{
+		Name:   Name,
+		Path:   "/webhooks/operatingsystemconfig",
+		Target: extensionswebhook.TargetSeed,
+		Mutators: map[extensionswebhook.Mutator][]extensionswebhook.Type{
+			NewMutator(mgr.GetClient(), DefaultAddOptions.Config, clock.RealClock{}): {{Obj: &extensionsv1alpha1.OperatingSystemConfig{}}},
+		},
+		NamespaceSelector: extensionswebhook.BuildExtensionTypeNamespaceSelector(gvisor.Type, nil),
+	})
+}